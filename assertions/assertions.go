@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"go.riyazali.net/httpx"
 	. "go.riyazali.net/httpx/helpers"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"reflect"
@@ -17,8 +18,10 @@ var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
 // ToHaveStatus returns an assertions that checks whether the request status matches the given status or not
 func ToHaveStatus(status int) httpx.Assertion {
 	return func(response *http.Response) error {
-		return AssertThat(response.StatusCode == status,
-			fmt.Sprintf("status: returned status (%d) not equal to expected status (%d)", response.StatusCode, status))
+		if response.StatusCode == status {
+			return nil
+		}
+		return statusError(response, statusText(status))
 	}
 }
 
@@ -26,36 +29,53 @@ func ToHaveStatus(status int) httpx.Assertion {
 // The given callback must be function with following signature,
 //    func cb(x X) error
 // where X can be any type that json.Decoder supports.
-func BodyJson(cb interface{}) httpx.Assertion {
+//
+// Use opts to perform checks before decoding the body, like EnforceJsonMediaType().
+func BodyJson(cb interface{}, opts ...BodyOption) httpx.Assertion {
+	return decodeBody("json", cb, opts, func(body io.Reader, v interface{}) error {
+		return json.NewDecoder(body).Decode(v)
+	})
+}
+
+// decodeBody returns an assertion that decodes the response body (using the given decode function) into a new instance
+// of the callback's argument type and invokes the callback with it. Errors are prefixed with the given format name.
+// It implements the shared logic behind BodyJson(...) and BodyXml(...).
+func decodeBody(format string, cb interface{}, opts []BodyOption, decode func(io.Reader, interface{}) error) httpx.Assertion {
 	// extract type and value of callback
 	var t = reflect.TypeOf(cb)
 	var v = reflect.ValueOf(cb)
 
 	// do some sanity checks
-	if t.Kind() != reflect.Func {
-		return failed(fmt.Errorf("json: given callback is not a function"))
+	if t == nil || t.Kind() != reflect.Func {
+		return failed(fmt.Errorf("%s: given callback is not a function", format))
 	} else if t.NumIn() != 1 || t.IsVariadic() {
-		return failed(fmt.Errorf("json: callback must only accept single argument"))
+		return failed(fmt.Errorf("%s: callback must only accept single argument", format))
 	} else if t.NumOut() != 1 || !t.Out(0).Implements(errorInterface) {
-		return failed(fmt.Errorf("json: callback must only return single value of type error"))
+		return failed(fmt.Errorf("%s: callback must only return single value of type error", format))
 	}
 
-	// return an Assertable that json decodes the response and invokes callback
+	// return an Assertable that decodes the response and invokes callback
 	return func(response *http.Response) (err error) {
 		defer checkClose(response.Body, &err)
 
+		for _, fn := range opts {
+			if err := fn(response); err != nil {
+				return fmt.Errorf("%s: %v", format, err)
+			}
+		}
+
 		// create a new instance of callback's 0th argument
 		var arg0 = t.In(0)
 		var obj = reflect.New(arg0)
 
-		if err := json.NewDecoder(response.Body).Decode(obj.Interface()); err != nil {
-			return fmt.Errorf("json: failed to decode response body: %v", err)
+		if err := decode(response.Body, obj.Interface()); err != nil {
+			return fmt.Errorf("%s: failed to decode response body: %v", format, err)
 		}
 
 		// invoke callback
 		var ret = v.Call([]reflect.Value{obj.Elem()})
 		if err, ok := ret[0].Interface().(error); ok {
-			return fmt.Errorf("json: %v", err)
+			return fmt.Errorf("%s: %v", format, err)
 		}
 		return nil
 	}
@@ -103,7 +123,8 @@ func HaveCookie(name string) httpx.Assertion {
 }
 
 // WithHeader returns an assertion which extracts the header value and invokes
-// the given handler with all the header values found in the response.
+// the given handler with the first header value found in the response (or an empty string if there's none).
+// Use WithHeaderValues(...) if you need all the values.
 func WithHeader(name string, hn func(string) error) httpx.Assertion {
 	return func(response *http.Response) error {
 		var header = response.Header.Get(name)
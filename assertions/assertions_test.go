@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -18,6 +19,41 @@ func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
 	}
 }
 
+// TestingT implementation that logs it's method calls
+type reporter map[string]int
+
+func (r reporter) Errorf(_ string, _ ...interface{}) { r["Errorf"] = r["Errorf"] + 1 }
+func (r reporter) FailNow()                          { r["FailNow"] = r["FailNow"] + 1 }
+func (r reporter) Helper()                           {}
+
+// newResponse returns the response recorded for the given status, header and body.
+// Headers named in the Trailer header are written after the body, and so are sent as trailers.
+func newResponse(status int, header http.Header, body string) *http.Response {
+	var trailers = make(map[string]bool)
+	for _, name := range header["Trailer"] {
+		trailers[http.CanonicalHeaderKey(name)] = true
+	}
+
+	var writer = httptest.NewRecorder()
+	for name, values := range header {
+		for _, value := range values {
+			if !trailers[http.CanonicalHeaderKey(name)] {
+				writer.Header().Add(name, value)
+			}
+		}
+	}
+	writer.WriteHeader(status)
+	_, _ = io.WriteString(writer, body)
+	for name, values := range header {
+		for _, value := range values {
+			if trailers[http.CanonicalHeaderKey(name)] {
+				writer.Header().Add(name, value)
+			}
+		}
+	}
+	return writer.Result()
+}
+
 type errorReader struct{}
 
 func (e *errorReader) Read(p []byte) (int, error) { return 0, errors.New("test") }
@@ -166,3 +202,35 @@ func TestBodyJson(t *testing.T) {
 		assert(t, err != nil, "should return error if fails to close response body")
 	})
 }
+
+func TestHeaderThat(t *testing.T) {
+	var writer = httptest.NewRecorder()
+	writer.Header().Set("content-type", "application/json")
+	var resp = writer.Result()
+
+	assert(t, HeaderThat("content-type", matchers.HasPrefix("application/"))(resp) == nil, "must match header value")
+	assert(t, HeaderThat("content-type", matchers.Equal("text/html"))(resp) != nil, "must fail if header doesn't match")
+}
+
+func TestCookieThat(t *testing.T) {
+	var writer = httptest.NewRecorder()
+	http.SetCookie(writer, &http.Cookie{Name: "a", Value: "1"})
+	var resp = writer.Result()
+
+	assert(t, CookieThat("a", matchers.Equal("1"))(resp) == nil, "must match cookie value")
+	assert(t, CookieThat("a", matchers.Equal("2"))(resp) != nil, "must fail if cookie doesn't match")
+	assert(t, CookieThat("b", matchers.Empty())(resp) != nil, "must fail if cookie is not set")
+}
+
+func TestJsonPath(t *testing.T) {
+	var newResponse = func(body string) *http.Response {
+		var writer = httptest.NewRecorder()
+		_, _ = io.WriteString(writer, body)
+		return writer.Result()
+	}
+
+	assert(t, JsonPath("users[0].age", matchers.Equal(42))(newResponse(`{"users": [{"age": 42}]}`)) == nil, "must match value at path")
+	assert(t, JsonPath("users[0].age", matchers.Equal(41))(newResponse(`{"users": [{"age": 42}]}`)) != nil, "must fail if value doesn't match")
+	assert(t, JsonPath("users[1]", matchers.Nil())(newResponse(`{"users": []}`)) != nil, "must fail if path doesn't exist")
+	assert(t, JsonPath("users", matchers.Nil())(newResponse(`{`)) != nil, "must fail if body is not json")
+}
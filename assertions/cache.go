@@ -0,0 +1,256 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl is a parsed Cache-Control header, mapping (lower-cased) directive names to their (unquoted) arguments.
+type CacheControl map[string]string
+
+// ParseCacheControl parses the given Cache-Control header values.
+func ParseCacheControl(values ...string) CacheControl {
+	var cc = make(CacheControl)
+	for _, directive := range ParseList(values...) {
+		var name, arg = splitDirective(directive, "=")
+		if name = strings.ToLower(name); name != "" {
+			if _, ok := cc[name]; !ok {
+				cc[name] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+	return cc
+}
+
+// Has returns true if the given directive is present
+func (cc CacheControl) Has(directive string) bool {
+	var _, ok = cc[strings.ToLower(directive)]
+	return ok
+}
+
+// Seconds returns the delta-seconds argument of the given directive (like max-age), and false if it's absent or invalid.
+func (cc CacheControl) Seconds(directive string) (time.Duration, bool) {
+	if arg, ok := cc[strings.ToLower(directive)]; ok {
+		if n, err := strconv.Atoi(arg); err == nil && n >= 0 {
+			return time.Duration(n) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// WithCacheControl returns an assertion which invokes the given handler with the parsed Cache-Control header.
+func WithCacheControl(hn func(CacheControl) error) httpx.Assertion {
+	return func(response *http.Response) error {
+		if err := hn(ParseCacheControl(response.Header.Values("Cache-Control")...)); err != nil {
+			return fmt.Errorf("cache: %v", err)
+		}
+		return nil
+	}
+}
+
+// ToBePublic returns an assertion that checks whether the response can be stored by shared caches (like a CDN),
+// ie. it is neither no-store nor private, and either is marked public or has an explicit expiration time.
+func ToBePublic() httpx.Assertion {
+	return func(response *http.Response) error {
+		var cc = ParseCacheControl(response.Header.Values("Cache-Control")...)
+		switch {
+		case cc.Has("no-store"):
+			return fmt.Errorf("cache: response is no-store")
+		case cc.Has("private"):
+			return fmt.Errorf("cache: response is private")
+		}
+		return AssertThat(cc.Has("public") || cc.Has("max-age") || cc.Has("s-maxage") || response.Header.Get("Expires") != "",
+			"cache: response is neither public nor has an explicit expiration time")
+	}
+}
+
+// ToBePrivate returns an assertion that checks whether the response can only be stored by private (browser) caches.
+func ToBePrivate() httpx.Assertion {
+	return WithCacheControl(func(cc CacheControl) error {
+		return Multiple(
+			AssertThat(!cc.Has("no-store"), "response is no-store"),
+			AssertThat(cc.Has("private"), "response is not private"),
+		)
+	})
+}
+
+// ToBeUncacheable returns an assertion that checks whether the response must not be stored by any cache (ie. no-store).
+func ToBeUncacheable() httpx.Assertion {
+	return WithCacheControl(func(cc CacheControl) error {
+		return AssertThat(cc.Has("no-store"), "response is not no-store")
+	})
+}
+
+// ToHaveFreshnessLifetime returns an assertion that checks the freshness lifetime of the response (in seconds) using
+// the given matcher. The lifetime is computed as a private cache would (see RFC 7234 section 4.2.1), using max-age,
+// or the difference between Expires and Date. Responses with neither have a lifetime of zero.
+//
+//  ToHaveFreshnessLifetime(matchers.Between(60, 3600))
+func ToHaveFreshnessLifetime(m matchers.Matcher) httpx.Assertion {
+	return func(response *http.Response) error {
+		if err := m(int(freshness(response).Seconds())); err != nil {
+			return fmt.Errorf("cache: freshness lifetime: %v", err)
+		}
+		return nil
+	}
+}
+
+// ToBeFreshFor returns an assertion that checks whether a cache can serve the response, without revalidating it,
+// for at least the given duration, ie. its freshness lifetime less its current age (using Age and Date) is at least d.
+// Responses that are no-store or no-cache are never fresh.
+func ToBeFreshFor(d time.Duration) httpx.Assertion {
+	return func(response *http.Response) error {
+		var cc = ParseCacheControl(response.Header.Values("Cache-Control")...)
+		if cc.Has("no-store") || cc.Has("no-cache") {
+			return fmt.Errorf("cache: response is no-store or no-cache")
+		}
+
+		var remaining = freshness(response) - age(response)
+		return AssertThat(remaining >= d, "cache: response is fresh for %v, expected at least %v", remaining, d)
+	}
+}
+
+// etag matches a valid (strong or weak) entity-tag
+var etag = regexp.MustCompile(`^(W/)?"[^"\x00-\x20\x7f]*"$`)
+
+// ToHaveETag returns an assertion that checks whether the response has a syntactically valid ETag.
+func ToHaveETag() httpx.Assertion {
+	return func(response *http.Response) error {
+		var value = response.Header.Get("ETag")
+		if value == "" {
+			return fmt.Errorf("cache: ETag not set")
+		}
+		return AssertThat(etag.MatchString(value), "cache: invalid ETag '%s'", value)
+	}
+}
+
+// ToVaryOn returns an assertion that checks whether the Vary header lists all the given request headers.
+// A Vary of "*" matches any header.
+func ToVaryOn(headers ...string) httpx.Assertion {
+	return func(response *http.Response) error {
+		var vary = ParseList(response.Header.Values("Vary")...)
+		for _, header := range headers {
+			if !allows(vary, header, true, false) {
+				return fmt.Errorf("cache: Vary %v does not include '%s'", vary, header)
+			}
+		}
+		return nil
+	}
+}
+
+// ToBeNotModified returns an assertion that checks whether the response is a 304 (Not Modified) without a body.
+func ToBeNotModified() httpx.Assertion {
+	return func(response *http.Response) (err error) {
+		defer checkClose(response.Body, &err)
+		if response.StatusCode != http.StatusNotModified {
+			return statusError(response, statusText(http.StatusNotModified))
+		}
+
+		var body []byte
+		if body, err = ioutil.ReadAll(response.Body); err != nil {
+			return fmt.Errorf("cache: failed to read response body: %v", err)
+		}
+		return AssertThat(len(body) == 0, "cache: 304 response has a body of %d bytes", len(body))
+	}
+}
+
+// Revalidate returns an assertion that re-issues the request (that the response is for) using the given ExecFn,
+// conditioned on the response's validators (If-None-Match with its ETag or, if absent, If-Modified-Since with its
+// Last-Modified), and runs the given assertions on the new response. If no assertions are given, it checks that
+// the new response is a 304 (see ToBeNotModified()). This makes testing cache revalidation a one-liner, like,
+//
+//  WithHandler(handler).MakeRequest(Get("/users/1")).ExpectIt(t, ToHaveETag(), Revalidate(WithHandler(handler)))
+//
+// Requests with a body are only re-issued if the body can be obtained again (using request.GetBody).
+func Revalidate(fn httpx.ExecFn, assertions ...httpx.Assertion) httpx.Assertion {
+	if len(assertions) == 0 {
+		assertions = []httpx.Assertion{ToBeNotModified()}
+	}
+
+	return func(response *http.Response) error {
+		if response.Request == nil {
+			return fmt.Errorf("revalidate: response has no associated request")
+		}
+
+		var request = response.Request.Clone(response.Request.Context())
+		request.Header.Del("If-Match")
+		request.Header.Del("If-Unmodified-Since")
+		if tag := response.Header.Get("ETag"); tag != "" {
+			request.Header.Set("If-None-Match", tag)
+			request.Header.Del("If-Modified-Since")
+		} else if lm := response.Header.Get("Last-Modified"); lm != "" {
+			request.Header.Set("If-Modified-Since", lm)
+			request.Header.Del("If-None-Match")
+		} else {
+			return fmt.Errorf("revalidate: response has neither ETag nor Last-Modified")
+		}
+
+		if response.Request.GetBody != nil {
+			var err error
+			if request.Body, err = response.Request.GetBody(); err != nil {
+				return fmt.Errorf("revalidate: failed to get request body: %v", err)
+			}
+		} else if request.ContentLength != 0 {
+			return fmt.Errorf("revalidate: request body cannot be re-read")
+		}
+		request.RequestURI = ""
+
+		var revalidated, err = fn(request)
+		if err != nil {
+			return fmt.Errorf("revalidate: failed to execute request: %v", err)
+		}
+		if revalidated == nil {
+			return fmt.Errorf("revalidate: executor returned no response")
+		}
+		defer func() { _ = revalidated.Body.Close() }()
+
+		if err = AllOf(assertions...)(revalidated); err != nil {
+			return fmt.Errorf("revalidate: %v", err)
+		}
+		return nil
+	}
+}
+
+// freshness returns the freshness lifetime of the response, as seen by a private cache
+func freshness(response *http.Response) time.Duration {
+	var cc = ParseCacheControl(response.Header.Values("Cache-Control")...)
+	if maxAge, ok := cc.Seconds("max-age"); ok {
+		return maxAge
+	}
+
+	if value := response.Header.Get("Expires"); value != "" {
+		var expires, err = http.ParseTime(value)
+		if err != nil {
+			return 0 // invalid dates (like "0") represent a time in the past
+		}
+		var date = time.Now()
+		if d, err := http.ParseTime(response.Header.Get("Date")); err == nil {
+			date = d
+		}
+		if lifetime := expires.Sub(date); lifetime > 0 {
+			return lifetime
+		}
+	}
+	return 0
+}
+
+// age returns the current age of the response, using both the Age and Date headers
+func age(response *http.Response) (age time.Duration) {
+	if n, err := strconv.Atoi(strings.TrimSpace(response.Header.Get("Age"))); err == nil && n > 0 {
+		age = time.Duration(n) * time.Second
+	}
+	if date, err := http.ParseTime(response.Header.Get("Date")); err == nil {
+		if apparent := time.Since(date); apparent > age {
+			age = apparent
+		}
+	}
+	return age
+}
@@ -0,0 +1,96 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/builders"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	var cc = ParseCacheControl(`Max-Age=60, private="Set-Cookie, X-Token"`, "max-age=10, no-cache")
+	assert(t, cc.Has("no-cache") && cc.Has("PRIVATE"), "must parse all directives")
+	assert(t, cc["private"] == "Set-Cookie, X-Token", "must unquote arguments")
+
+	var maxAge, ok = cc.Seconds("max-age")
+	assert(t, ok && maxAge == time.Minute, "must use first occurrence of directive")
+	_, ok = ParseCacheControl("max-age=abc").Seconds("max-age")
+	assert(t, !ok, "must reject invalid delta-seconds")
+}
+
+func TestCacheability(t *testing.T) {
+	var public = newResponse(http.StatusOK, http.Header{"Cache-Control": {"public, max-age=600"}}, "")
+	var private = newResponse(http.StatusOK, http.Header{"Cache-Control": {"private, max-age=600"}}, "")
+	var noStore = newResponse(http.StatusOK, http.Header{"Cache-Control": {"no-store"}}, "")
+	var expires = newResponse(http.StatusOK, http.Header{"Expires": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}, "")
+
+	assert(t, ToBePublic()(public) == nil, "must pass for public response")
+	assert(t, ToBePublic()(expires) == nil, "must pass for response with expires")
+	assert(t, ToBePublic()(private) != nil, "must fail for private response")
+	assert(t, ToBePublic()(newResponse(http.StatusOK, nil, "")) != nil, "must fail without explicit expiration")
+	assert(t, ToBePrivate()(private) == nil, "must pass for private response")
+	assert(t, ToBePrivate()(public) != nil, "must fail for public response")
+	assert(t, ToBeUncacheable()(noStore) == nil, "must pass for no-store response")
+	assert(t, ToBeUncacheable()(public) != nil, "must fail for cacheable response")
+}
+
+func TestFreshness(t *testing.T) {
+	var now = time.Now().UTC()
+	var aged = newResponse(http.StatusOK, http.Header{"Cache-Control": {"max-age=600"}, "Age": {"300"}}, "")
+	assert(t, ToHaveFreshnessLifetime(matchers.Equal(600))(aged) == nil, "must use max-age")
+	assert(t, ToBeFreshFor(4*time.Minute)(aged) == nil, "must subtract age")
+	assert(t, ToBeFreshFor(6*time.Minute)(aged) != nil, "must fail if not fresh for long enough")
+
+	var expires = newResponse(http.StatusOK, http.Header{
+		"Date":    {now.Format(http.TimeFormat)},
+		"Expires": {now.Add(time.Hour).Format(http.TimeFormat)},
+	}, "")
+	assert(t, ToHaveFreshnessLifetime(matchers.Equal(3600))(expires) == nil, "must use expires and date")
+	assert(t, ToHaveFreshnessLifetime(matchers.Equal(0))(newResponse(http.StatusOK, http.Header{"Expires": {"0"}}, "")) == nil, "must treat invalid expires as expired")
+	assert(t, ToBeFreshFor(0)(newResponse(http.StatusOK, http.Header{"Cache-Control": {"no-cache, max-age=60"}}, "")) != nil, "must fail for no-cache")
+}
+
+func TestValidators(t *testing.T) {
+	assert(t, ToHaveETag()(newResponse(http.StatusOK, http.Header{"ETag": {`W/"abc"`}}, "")) == nil, "must accept weak etag")
+	assert(t, ToHaveETag()(newResponse(http.StatusOK, http.Header{"ETag": {`abc`}}, "")) != nil, "must reject unquoted etag")
+	assert(t, ToHaveETag()(newResponse(http.StatusOK, nil, "")) != nil, "must fail if not set")
+
+	var vary = newResponse(http.StatusOK, http.Header{"Vary": {"Accept-Encoding, origin"}}, "")
+	assert(t, ToVaryOn("accept-encoding", "Origin")(vary) == nil, "must match headers case-insensitively")
+	assert(t, ToVaryOn("Accept")(vary) != nil, "must fail if header is not listed")
+	assert(t, ToVaryOn("Accept")(newResponse(http.StatusOK, http.Header{"Vary": {"*"}}, "")) == nil, "must match wildcard")
+}
+
+func TestRevalidate(t *testing.T) {
+	var modified = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/etag" {
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		http.ServeContent(w, r, "data.txt", modified, strings.NewReader("hello world"))
+	})
+
+	WithHandler(handler).MakeRequest(Get("/etag")).
+		ExpectIt(t, ToBeFreshFor(time.Minute), ToHaveETag(), Revalidate(WithHandler(handler)))
+	WithHandler(handler).MakeRequest(Get("/last-modified")).
+		ExpectIt(t, Revalidate(WithHandler(handler)))
+	WithHandler(handler).MakeRequest(Get("/etag"), builders.WithIfNoneMatch(`"v1"`)).
+		ExpectIt(t, ToBeNotModified())
+	WithHandler(handler).MakeRequest(Get("/etag"), builders.WithIfNoneMatch(`"v0"`)).
+		ExpectIt(t, ToHaveStatus(http.StatusOK), Revalidate(WithHandler(handler), ToHaveStatus(http.StatusNotModified)))
+
+	var stale = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		_, _ = w.Write([]byte("changed"))
+	})
+	var response = newResponse(http.StatusOK, http.Header{"ETag": {`"v1"`}}, "")
+	response.Request, _ = http.NewRequest(http.MethodGet, "/etag", nil)
+	assert(t, Revalidate(WithHandler(stale))(response) != nil, "must fail if resource is not revalidated")
+	assert(t, Revalidate(WithHandler(stale))(newResponse(http.StatusOK, http.Header{"ETag": {`"v1"`}}, "")) != nil, "must fail without request")
+}
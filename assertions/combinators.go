@@ -0,0 +1,125 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"net/http"
+	"strings"
+)
+
+// Not returns an assertion that passes only if the given assertion fails.
+// A fatal failure (see httpx.Require(...)) is not inverted, and is returned as is.
+func Not(a httpx.Assertion) httpx.Assertion {
+	return func(response *http.Response) error {
+		var rewind, err = BufferBody(response)
+		if err != nil {
+			return fmt.Errorf("not: failed to read response body: %v", err)
+		}
+		defer rewind()
+
+		if err = a(response); httpx.IsFatal(err) {
+			return err
+		}
+		return AssertThat(err != nil, "not: assertion passed unexpectedly")
+	}
+}
+
+// AllOf returns an assertion that passes only if all the given assertions pass.
+// All assertions are evaluated (unless one fails fatally, see httpx.Require(...)) and every failure is reported.
+func AllOf(assertions ...httpx.Assertion) httpx.Assertion {
+	return func(response *http.Response) error {
+		var errs, fatal, err = evaluate(response, assertions)
+		if err != nil {
+			return fmt.Errorf("all of: failed to read response body: %v", err)
+		}
+
+		var failed = 0
+		for _, e := range errs {
+			if e != nil {
+				failed++
+			}
+		}
+		if failed == 0 {
+			return nil
+		}
+
+		err = fmt.Errorf("all of: %d of %d failed:%s", failed, len(assertions), list(errs))
+		if fatal {
+			return httpx.Fatal(err)
+		}
+		return err
+	}
+}
+
+// AnyOf returns an assertion that passes if at least one of the given assertions pass.
+// Assertions are evaluated in order, and evaluation stops at the first assertion that passes.
+func AnyOf(assertions ...httpx.Assertion) httpx.Assertion {
+	return func(response *http.Response) error {
+		var rewind, err = BufferBody(response)
+		if err != nil {
+			return fmt.Errorf("any of: failed to read response body: %v", err)
+		}
+		defer rewind()
+
+		var errs = make([]error, len(assertions))
+		for i, fn := range assertions {
+			rewind()
+			if errs[i] = fn(response); errs[i] == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("any of: none of %d passed:%s", len(assertions), list(errs))
+	}
+}
+
+// When returns an assertion that evaluates the given assertions (just like AllOf(...)) only if the predicate passes.
+// The predicate is itself an assertion, and so any existing assertion can be used as a condition, like,
+//
+//  When(ToHaveStatus(http.StatusOK), BodyJson(func(x X) error { ... }))
+func When(predicate httpx.Assertion, assertions ...httpx.Assertion) httpx.Assertion {
+	return func(response *http.Response) error {
+		var rewind, err = BufferBody(response)
+		if err != nil {
+			return fmt.Errorf("when: failed to read response body: %v", err)
+		}
+		defer rewind()
+
+		if predicate(response) != nil {
+			return nil
+		}
+		rewind()
+		return AllOf(assertions...)(response)
+	}
+}
+
+// evaluate runs the given assertions on the response, rewinding its body before each of them, and returns
+// the errors returned by each of them (nil for the ones that passed). Evaluation stops at the first fatal error.
+func evaluate(response *http.Response, assertions []httpx.Assertion) (errs []error, fatal bool, err error) {
+	var rewind func()
+	if rewind, err = BufferBody(response); err != nil {
+		return nil, false, err
+	}
+	defer rewind()
+
+	errs = make([]error, len(assertions))
+	for i, fn := range assertions {
+		rewind()
+		if errs[i] = fn(response); httpx.IsFatal(errs[i]) {
+			return errs, true, nil
+		}
+	}
+	return errs, false, nil
+}
+
+// list formats the given errors (skipping nil ones) as an indented list.
+// Multi-line error messages (say, from nested combinators) are indented further.
+func list(errs []error) string {
+	var buf strings.Builder
+	for i, err := range errs {
+		if err != nil {
+			_, _ = fmt.Fprintf(&buf, "\n  - [%d] %s", i+1, strings.Replace(err.Error(), "\n", "\n    ", -1))
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,65 @@
+package assertions_test
+
+import (
+	"errors"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var pass = httpx.Assertion(func(*http.Response) error { return nil })
+var fail = httpx.Assertion(func(*http.Response) error { return errors.New("test") })
+
+// readsBody is an assertion that passes only if it can read the complete body
+var readsBody = httpx.Assertion(func(response *http.Response) error {
+	var body, _ = ioutil.ReadAll(response.Body)
+	if string(body) != "hello world" {
+		return errors.New("body not readable")
+	}
+	return nil
+})
+
+func TestNot(t *testing.T) {
+	assert(t, Not(fail)(newResponse(http.StatusOK, nil, "hello world")) == nil, "must pass if assertion fails")
+	assert(t, Not(pass)(newResponse(http.StatusOK, nil, "hello world")) != nil, "must fail if assertion passes")
+	assert(t, httpx.IsFatal(Not(httpx.Require(fail))(newResponse(http.StatusOK, nil, "hello world"))), "must pass fatal failures through")
+
+	var resp = newResponse(http.StatusOK, nil, "hello world")
+	_ = Not(readsBody)(resp)
+	assert(t, readsBody(resp) == nil, "must rewind body after evaluation")
+}
+
+func TestAllOf(t *testing.T) {
+	assert(t, AllOf(readsBody, readsBody, pass)(newResponse(http.StatusOK, nil, "hello world")) == nil, "must pass if all assertions pass")
+	assert(t, AllOf(pass, fail)(newResponse(http.StatusOK, nil, "hello world")) != nil, "must fail if any assertion fails")
+	assert(t, AllOf()(newResponse(http.StatusOK, nil, "hello world")) == nil, "must pass if no assertions are given")
+
+	t.Run("should report nested failures", func(t *testing.T) {
+		var err = AllOf(fail, pass, AllOf(fail, fail))(newResponse(http.StatusOK, nil, "hello world"))
+		var msg = err.Error()
+		assert(t, strings.HasPrefix(msg, "all of: 2 of 3 failed:"), "must count failures: %s", msg)
+		assert(t, strings.Contains(msg, "\n  - [3] all of: 2 of 2 failed:\n      - [1] test"), "must indent nested failures: %s", msg)
+	})
+
+	t.Run("should stop on fatal failures", func(t *testing.T) {
+		var called bool
+		var err = AllOf(httpx.Require(fail), func(*http.Response) error { called = true; return nil })(newResponse(http.StatusOK, nil, "hello world"))
+		assert(t, httpx.IsFatal(err), "must preserve fatal failures")
+		assert(t, !called, "must not evaluate remaining assertions")
+	})
+}
+
+func TestAnyOf(t *testing.T) {
+	assert(t, AnyOf(fail, readsBody)(newResponse(http.StatusOK, nil, "hello world")) == nil, "must pass if any assertion passes")
+	assert(t, AnyOf(fail, fail)(newResponse(http.StatusOK, nil, "hello world")) != nil, "must fail if all assertions fail")
+	assert(t, AnyOf()(newResponse(http.StatusOK, nil, "hello world")) != nil, "must fail if no assertions are given")
+}
+
+func TestWhen(t *testing.T) {
+	assert(t, When(fail, fail)(newResponse(http.StatusOK, nil, "hello world")) == nil, "must not evaluate assertions if predicate fails")
+	assert(t, When(readsBody, fail)(newResponse(http.StatusOK, nil, "hello world")) != nil, "must evaluate assertions if predicate passes")
+	assert(t, When(readsBody, readsBody)(newResponse(http.StatusOK, nil, "hello world")) == nil, "must rewind body between predicate and assertions")
+}
@@ -0,0 +1,154 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentTypeCheck defines a function that performs additional checks on a response's parsed media type.
+// See WithCharset(...) and ValidCharset(...) for the checks shipped with this package.
+type ContentTypeCheck func(response *http.Response, mediaType string, params map[string]string) error
+
+// ToHaveContentType returns an assertion that parses the Content-Type header (using mime.ParseMediaType)
+// and checks whether its media type matches the expected one. Type and subtype are compared case-insensitively,
+// and the expected media type may contain wildcards, like "text/*" or "*/*", or a wildcard with a structured
+// syntax suffix, like "application/*+json" (which matches "application/problem+json" etc.)
+//
+// Use checks to check the media type's parameters too, like,
+//
+//  ToHaveContentType("application/json", WithCharset("utf-8"), ValidCharset())
+func ToHaveContentType(expected string, checks ...ContentTypeCheck) httpx.Assertion {
+	return func(response *http.Response) error {
+		var header = response.Header.Get("Content-Type")
+		if header == "" {
+			return fmt.Errorf("content-type: header not set")
+		}
+
+		var mediaType, params, err = mime.ParseMediaType(header)
+		if err != nil {
+			return fmt.Errorf("content-type: failed to parse '%s': %v", header, err)
+		}
+		if !matchMediaType(expected, mediaType) {
+			return fmt.Errorf("content-type: media type (%s) does not match expected media type (%s)", mediaType, expected)
+		}
+
+		for _, fn := range checks {
+			if err := fn(response, mediaType, params); err != nil {
+				return fmt.Errorf("content-type: %v", err)
+			}
+		}
+		return nil
+	}
+}
+
+// WithCharset returns a ContentTypeCheck that checks whether the charset parameter is equal (case-insensitively)
+// to the given charset.
+func WithCharset(charset string) ContentTypeCheck {
+	return func(_ *http.Response, _ string, params map[string]string) error {
+		if actual, ok := params["charset"]; !ok {
+			return fmt.Errorf("charset not set")
+		} else if !strings.EqualFold(normalizeCharset(actual), normalizeCharset(charset)) {
+			return fmt.Errorf("charset (%s) not equal to expected charset (%s)", actual, charset)
+		}
+		return nil
+	}
+}
+
+// ValidCharset returns a ContentTypeCheck that checks whether the response body actually decodes
+// in the charset declared by the charset parameter (defaulting to utf-8 if not set).
+// Only utf-8, us-ascii and iso-8859-1 are supported; other charsets fail the check.
+func ValidCharset() ContentTypeCheck {
+	return func(response *http.Response, _ string, params map[string]string) (err error) {
+		defer checkClose(response.Body, &err)
+
+		var body []byte
+		if body, err = ioutil.ReadAll(response.Body); err != nil {
+			return fmt.Errorf("failed to read response body: %v", err)
+		}
+
+		var charset = normalizeCharset(params["charset"])
+		switch charset {
+		case "", "utf-8":
+			if !utf8.Valid(body) {
+				return fmt.Errorf("body is not valid utf-8")
+			}
+		case "us-ascii":
+			for i, b := range body {
+				if b > 0x7f {
+					return fmt.Errorf("body is not valid us-ascii (non-ascii byte at offset %d)", i)
+				}
+			}
+		case "iso-8859-1":
+			// every byte sequence is valid iso-8859-1
+		default:
+			return fmt.Errorf("cannot validate body in charset '%s'", charset)
+		}
+		return nil
+	}
+}
+
+// BodyOption defines a function that performs a check on the response before its body is decoded
+// by assertions like BodyJson(...). See EnforceMediaType(...) and EnforceJsonMediaType().
+type BodyOption func(*http.Response) error
+
+// EnforceMediaType returns a BodyOption that makes sure the response's media type matches the expected
+// one (see ToHaveContentType(...) for the matching rules) before its body is decoded.
+func EnforceMediaType(expected string) BodyOption {
+	return BodyOption(ToHaveContentType(expected))
+}
+
+// EnforceJsonMediaType returns a BodyOption that makes sure the response's media type is either
+// application/json or has a +json structured syntax suffix (like application/problem+json) before its body is decoded.
+func EnforceJsonMediaType() BodyOption {
+	return func(response *http.Response) error {
+		var err = ToHaveContentType("application/json")(response)
+		if err != nil && ToHaveContentType("*/*+json")(response) == nil {
+			return nil
+		}
+		return err
+	}
+}
+
+// matchMediaType reports whether the media type matches the (possibly wildcard) pattern
+func matchMediaType(pattern, mediaType string) bool {
+	var pt, ps = splitMediaType(pattern)
+	var at, as = splitMediaType(mediaType)
+	if pt != "*" && pt != at {
+		return false
+	}
+	switch {
+	case ps == "*" || ps == as:
+		return true
+	case strings.HasPrefix(ps, "*+"):
+		return strings.HasSuffix(as, ps[1:])
+	}
+	return false
+}
+
+// splitMediaType splits the media type into lower-cased type and subtype
+func splitMediaType(mediaType string) (string, string) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if i := strings.IndexByte(mediaType, '/'); i >= 0 {
+		return mediaType[:i], mediaType[i+1:]
+	}
+	return mediaType, ""
+}
+
+// normalizeCharset lower-cases the given charset and maps common aliases to their canonical names
+func normalizeCharset(charset string) string {
+	charset = strings.ToLower(strings.Trim(charset, `" `))
+	switch charset {
+	case "utf8":
+		return "utf-8"
+	case "ascii", "us_ascii":
+		return "us-ascii"
+	case "latin1", "iso8859-1", "iso_8859-1":
+		return "iso-8859-1"
+	}
+	return charset
+}
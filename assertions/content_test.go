@@ -0,0 +1,50 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx/assertions"
+	"net/http"
+	"testing"
+)
+
+// contentType returns a header with the given Content-Type
+func contentType(value string) http.Header {
+	return http.Header{"Content-Type": {value}}
+}
+
+func TestToHaveContentType(t *testing.T) {
+	var json = func() *http.Response {
+		return newResponse(http.StatusOK, contentType("Application/JSON; charset=UTF-8"), `{}`)
+	}
+
+	assert(t, ToHaveContentType("application/json")(json()) == nil, "must match case-insensitively")
+	assert(t, ToHaveContentType("application/*")(json()) == nil, "must match subtype wildcard")
+	assert(t, ToHaveContentType("*/*")(json()) == nil, "must match full wildcard")
+	assert(t, ToHaveContentType("text/json")(json()) != nil, "must fail if type differs")
+	assert(t, ToHaveContentType("application/xml")(json()) != nil, "must fail if subtype differs")
+	assert(t, ToHaveContentType("application/*+json")(newResponse(http.StatusOK, contentType("application/problem+json"), "")) == nil, "must match structured suffix")
+	assert(t, ToHaveContentType("application/*+json")(json()) != nil, "must not match without structured suffix")
+	assert(t, ToHaveContentType("application/json")(newResponse(http.StatusOK, nil, "")) != nil, "must fail if header is missing")
+	assert(t, ToHaveContentType("application/json")(newResponse(http.StatusOK, contentType("application/"), "")) != nil, "must fail if header is malformed")
+}
+
+func TestContentTypeChecks(t *testing.T) {
+	assert(t, ToHaveContentType("text/plain", WithCharset("utf8"))(newResponse(http.StatusOK, contentType("text/plain; charset=UTF-8"), "")) == nil, "must match charset aliases")
+	assert(t, ToHaveContentType("text/plain", WithCharset("utf-8"))(newResponse(http.StatusOK, contentType("text/plain; charset=latin1"), "")) != nil, "must fail if charset differs")
+	assert(t, ToHaveContentType("text/plain", WithCharset("utf-8"))(newResponse(http.StatusOK, contentType("text/plain"), "")) != nil, "must fail if charset is not set")
+
+	assert(t, ToHaveContentType("text/plain", ValidCharset())(newResponse(http.StatusOK, contentType("text/plain"), "héllo")) == nil, "must validate utf-8 by default")
+	assert(t, ToHaveContentType("text/plain", ValidCharset())(newResponse(http.StatusOK, contentType("text/plain"), "\xff")) != nil, "must fail on invalid utf-8")
+	assert(t, ToHaveContentType("text/plain", ValidCharset())(newResponse(http.StatusOK, contentType("text/plain; charset=us-ascii"), "héllo")) != nil, "must fail on non-ascii bytes")
+	assert(t, ToHaveContentType("text/plain", ValidCharset())(newResponse(http.StatusOK, contentType("text/plain; charset=iso-8859-1"), "\xff")) == nil, "must accept any byte in latin1")
+	assert(t, ToHaveContentType("text/plain", ValidCharset())(newResponse(http.StatusOK, contentType("text/plain; charset=koi8-r"), "")) != nil, "must fail on unsupported charset")
+}
+
+func TestBodyJsonEnforceMediaType(t *testing.T) {
+	var cb = func(map[string]interface{}) error { return nil }
+
+	assert(t, BodyJson(cb, EnforceJsonMediaType())(newResponse(http.StatusOK, contentType("application/json"), `{}`)) == nil, "must accept application/json")
+	assert(t, BodyJson(cb, EnforceJsonMediaType())(newResponse(http.StatusOK, contentType("application/vnd.api+json"), `{}`)) == nil, "must accept +json suffix")
+	assert(t, BodyJson(cb, EnforceJsonMediaType())(newResponse(http.StatusOK, contentType("text/html"), `{}`)) != nil, "must reject other media types")
+	assert(t, BodyJson(cb, EnforceMediaType("text/*"))(newResponse(http.StatusOK, contentType("text/plain"), `{}`)) == nil, "must enforce given media type")
+	assert(t, BodyJson(cb)(newResponse(http.StatusOK, contentType("text/html"), `{}`)) == nil, "must not enforce media type by default")
+}
@@ -0,0 +1,183 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CookieCheck defines a function that checks a single attribute of a cookie.
+type CookieCheck func(*http.Cookie) error
+
+// ToHaveCookie returns an assertion that checks whether a cookie with the given name is set in the response
+// and passes all the given checks. If multiple Set-Cookie headers (with different paths or domains) set a cookie
+// with the given name, all of them must pass the checks. Unlike WithCookie(...), it fails if the same cookie
+// (ie. same name, path and domain) is set more than once.
+//
+//  ToHaveCookie("session", CookieSecure(), CookieHttpOnly(), CookieSameSite(http.SameSiteStrictMode))
+func ToHaveCookie(name string, checks ...CookieCheck) httpx.Assertion {
+	return func(response *http.Response) error {
+		var cookies []*http.Cookie
+		for _, cookie := range response.Cookies() {
+			if cookie.Name == name {
+				cookies = append(cookies, cookie)
+			}
+		}
+		if len(cookies) == 0 {
+			return fmt.Errorf("cookie: cookie with name '%s' not set", name)
+		}
+		if err := duplicates(cookies); err != nil {
+			return err
+		}
+
+		for _, cookie := range cookies {
+			for _, fn := range checks {
+				if err := fn(cookie); err != nil {
+					return fmt.Errorf("cookie: %s: %v", name, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// NoDuplicateCookies returns an assertion that checks that no cookie (ie. same name, path and domain)
+// is set more than once in the response.
+func NoDuplicateCookies() httpx.Assertion {
+	return func(response *http.Response) error {
+		return duplicates(response.Cookies())
+	}
+}
+
+// CookieValue returns a CookieCheck that checks the cookie's value using the given matcher.
+func CookieValue(m matchers.Matcher) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		if err := m(cookie.Value); err != nil {
+			return fmt.Errorf("value: %v", err)
+		}
+		return nil
+	}
+}
+
+// CookieSecure returns a CookieCheck that checks whether the cookie has the Secure attribute.
+func CookieSecure() CookieCheck {
+	return func(cookie *http.Cookie) error {
+		return AssertThat(cookie.Secure, "not marked Secure")
+	}
+}
+
+// CookieHttpOnly returns a CookieCheck that checks whether the cookie has the HttpOnly attribute.
+func CookieHttpOnly() CookieCheck {
+	return func(cookie *http.Cookie) error {
+		return AssertThat(cookie.HttpOnly, "not marked HttpOnly")
+	}
+}
+
+// CookieSameSite returns a CookieCheck that checks whether the cookie's SameSite attribute has the given value.
+func CookieSameSite(mode http.SameSite) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		return AssertThat(cookie.SameSite == mode,
+			"SameSite (%s) not equal to expected SameSite (%s)", sameSite(cookie.SameSite), sameSite(mode))
+	}
+}
+
+// CookiePath returns a CookieCheck that checks whether the cookie's Path attribute is equal to the given path.
+func CookiePath(path string) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		return AssertThat(cookie.Path == path, "path (%s) not equal to expected path (%s)", cookie.Path, path)
+	}
+}
+
+// CookieDomain returns a CookieCheck that checks whether the cookie's Domain attribute is equal to the given domain.
+// A leading dot is ignored on both, as is the case, in RFC 6265.
+func CookieDomain(domain string) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		return AssertThat(strings.EqualFold(strings.TrimPrefix(cookie.Domain, "."), strings.TrimPrefix(domain, ".")),
+			"domain (%s) not equal to expected domain (%s)", cookie.Domain, domain)
+	}
+}
+
+// CookieMaxAge returns a CookieCheck that checks the cookie's Max-Age attribute (in seconds) using the given matcher.
+// The check fails if Max-Age is not set.
+func CookieMaxAge(m matchers.Matcher) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		if cookie.MaxAge == 0 {
+			return fmt.Errorf("max-age not set")
+		}
+
+		var maxAge = cookie.MaxAge
+		if maxAge < 0 {
+			maxAge = 0 // net/http represents Max-Age=0 (and negative values) as -1
+		}
+		if err := m(maxAge); err != nil {
+			return fmt.Errorf("max-age: %v", err)
+		}
+		return nil
+	}
+}
+
+// CookieExpiresAfter returns a CookieCheck that checks whether the cookie lives for at least the given duration,
+// using either Max-Age or Expires attribute (Max-Age takes precedence). Session cookies fail the check.
+func CookieExpiresAfter(d time.Duration) CookieCheck {
+	return func(cookie *http.Cookie) error {
+		var lifetime, ok = lifetime(cookie)
+		if !ok {
+			return fmt.Errorf("is a session cookie (neither max-age nor expires set)")
+		}
+		return AssertThat(lifetime >= d, "expires in %v, expected at least %v", lifetime.Round(time.Second), d)
+	}
+}
+
+// CookieDeleted returns a CookieCheck that checks whether the cookie is being deleted,
+// ie. it has a Max-Age of zero (or less), or an Expires in the past.
+func CookieDeleted() CookieCheck {
+	return func(cookie *http.Cookie) error {
+		var lifetime, ok = lifetime(cookie)
+		return AssertThat(ok && lifetime <= 0, "not being deleted")
+	}
+}
+
+// lifetime returns the remaining lifetime of the cookie, and false if it's a session cookie
+func lifetime(cookie *http.Cookie) (time.Duration, bool) {
+	switch {
+	case cookie.MaxAge < 0:
+		return 0, true
+	case cookie.MaxAge > 0:
+		return time.Duration(cookie.MaxAge) * time.Second, true
+	case cookie.RawExpires != "" && !cookie.Expires.IsZero():
+		return time.Until(cookie.Expires), true
+	}
+	return 0, false
+}
+
+// duplicates returns an error if any cookie (same name, path and domain) is set more than once
+func duplicates(cookies []*http.Cookie) error {
+	var seen = make(map[string]bool)
+	for _, cookie := range cookies {
+		var key = strings.Join([]string{cookie.Name, cookie.Path, strings.ToLower(strings.TrimPrefix(cookie.Domain, "."))}, "\x00")
+		if seen[key] {
+			return fmt.Errorf("cookie: cookie with name '%s' set more than once", cookie.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// sameSite returns the attribute value for the given mode
+func sameSite(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	case http.SameSiteDefaultMode:
+		return "default"
+	}
+	return "not set"
+}
@@ -0,0 +1,61 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// setCookies returns a header that sets each of the given cookies
+func setCookies(cookies ...string) http.Header {
+	return http.Header{"Set-Cookie": cookies}
+}
+
+func TestToHaveCookie(t *testing.T) {
+	var session = newResponse(http.StatusOK, setCookies("session=abc; Path=/; Domain=.example.com; Max-Age=3600; Secure; HttpOnly; SameSite=Strict"), "")
+
+	assert(t, ToHaveCookie("session")(session) == nil, "must pass if cookie is set")
+	assert(t, ToHaveCookie("other")(session) != nil, "must fail if cookie is not set")
+	assert(t, ToHaveCookie("session",
+		CookieValue(matchers.Equal("abc")),
+		CookieSecure(),
+		CookieHttpOnly(),
+		CookieSameSite(http.SameSiteStrictMode),
+		CookiePath("/"),
+		CookieDomain("example.com"),
+		CookieMaxAge(matchers.Equal(3600)),
+		CookieExpiresAfter(time.Hour),
+	)(session) == nil, "must pass all checks")
+
+	var insecure = newResponse(http.StatusOK, setCookies("session=abc; SameSite=Lax"), "")
+	assert(t, ToHaveCookie("session", CookieSecure())(insecure) != nil, "must fail if not secure")
+	assert(t, ToHaveCookie("session", CookieHttpOnly())(insecure) != nil, "must fail if not http only")
+	assert(t, ToHaveCookie("session", CookieSameSite(http.SameSiteStrictMode))(insecure) != nil, "must fail if same site differs")
+	assert(t, ToHaveCookie("session", CookiePath("/"))(insecure) != nil, "must fail if path differs")
+	assert(t, ToHaveCookie("session", CookieDomain("example.com"))(insecure) != nil, "must fail if domain differs")
+	assert(t, ToHaveCookie("session", CookieMaxAge(matchers.GreaterThan(0)))(insecure) != nil, "must fail if max-age is not set")
+	assert(t, ToHaveCookie("session", CookieExpiresAfter(time.Second))(insecure) != nil, "must fail for session cookie")
+}
+
+func TestCookieExpiry(t *testing.T) {
+	var future = time.Now().Add(2 * time.Hour).UTC().Format(http.TimeFormat)
+	assert(t, ToHaveCookie("a", CookieExpiresAfter(time.Hour))(newResponse(http.StatusOK, setCookies("a=1; Expires="+future), "")) == nil, "must use expires")
+	assert(t, ToHaveCookie("a", CookieExpiresAfter(3*time.Hour))(newResponse(http.StatusOK, setCookies("a=1; Expires="+future), "")) != nil, "must fail if expires too soon")
+
+	assert(t, ToHaveCookie("a", CookieDeleted())(newResponse(http.StatusOK, setCookies("a=; Max-Age=0"), "")) == nil, "must detect deletion using max-age")
+	assert(t, ToHaveCookie("a", CookieDeleted())(newResponse(http.StatusOK, setCookies("a=; Expires=Thu, 01 Jan 1970 00:00:00 GMT"), "")) == nil, "must detect deletion using expires")
+	assert(t, ToHaveCookie("a", CookieDeleted())(newResponse(http.StatusOK, setCookies("a=1"), "")) != nil, "must fail if cookie is not being deleted")
+	assert(t, ToHaveCookie("a", CookieMaxAge(matchers.Equal(0)))(newResponse(http.StatusOK, setCookies("a=; Max-Age=0"), "")) == nil, "must report zero max-age")
+}
+
+func TestDuplicateCookies(t *testing.T) {
+	var duplicate = newResponse(http.StatusOK, setCookies("a=1; Path=/", "a=2; Path=/"), "")
+	var distinct = newResponse(http.StatusOK, setCookies("a=1; Path=/", "a=2; Path=/admin"), "")
+
+	assert(t, NoDuplicateCookies()(duplicate) != nil, "must detect duplicate cookies")
+	assert(t, NoDuplicateCookies()(distinct) == nil, "must allow same name with different paths")
+	assert(t, ToHaveCookie("a")(duplicate) != nil, "must fail on duplicate cookies")
+	assert(t, ToHaveCookie("a", CookieValue(matchers.Equal("1")))(distinct) != nil, "must check all cookies with the name")
+}
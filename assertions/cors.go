@@ -0,0 +1,158 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The assertions in this file validate CORS responses the way browsers do (see https://fetch.spec.whatwg.org/#http-cors-protocol).
+// They read the Origin and Access-Control-Request-* headers from response.Request, and so work with any executor
+// that sets it (like WithClient(...) and WithHandler(...)). Use them with builders.WithOrigin(...) and builders.WithPreflight(...).
+
+// ToAllowOrigin returns an assertion that checks whether the response passes the CORS check for the request's origin,
+// ie. Access-Control-Allow-Origin matches the origin. If credentials is true, the check is done for a request
+// with credentials (cookies or http auth), which additionally requires Access-Control-Allow-Credentials and disallows wildcards.
+func ToAllowOrigin(credentials bool) httpx.Assertion {
+	return func(response *http.Response) error {
+		var origin, err = requestOrigin(response)
+		if err != nil {
+			return err
+		}
+		return corsCheck(response, origin, credentials)
+	}
+}
+
+// ToAllowPreflight returns an assertion that checks whether the response to a preflight request allows the actual request,
+// ie. the response has an ok status, passes the CORS check, and allows the requested method and headers.
+// See ToAllowOrigin(...) for the meaning of credentials.
+func ToAllowPreflight(credentials bool) httpx.Assertion {
+	return func(response *http.Response) error {
+		var origin, err = requestOrigin(response)
+		if err != nil {
+			return err
+		}
+		return preflightCheck(response, origin, credentials)
+	}
+}
+
+// ToRejectOrigin returns an assertion that checks whether the request's origin is denied access, ie. the CORS check fails
+// for both requests with and without credentials. For preflight requests, the whole preflight check must fail.
+func ToRejectOrigin() httpx.Assertion {
+	return func(response *http.Response) error {
+		var origin, err = requestOrigin(response)
+		if err != nil {
+			return err
+		}
+
+		var check = corsCheck
+		if isPreflight(response.Request) {
+			check = preflightCheck
+		}
+		return AssertThat(check(response, origin, false) != nil && check(response, origin, true) != nil,
+			"cors: origin '%s' allowed unexpectedly", origin)
+	}
+}
+
+// PreflightMaxAge returns an assertion that checks the number of seconds the preflight response can be cached for
+// using the given matcher. As browsers do, a missing Access-Control-Max-Age means 5 seconds.
+//
+//  PreflightMaxAge(matchers.Between(600, 7200))
+func PreflightMaxAge(m matchers.Matcher) httpx.Assertion {
+	return func(response *http.Response) error {
+		var maxAge = 5
+		if value := response.Header.Get("Access-Control-Max-Age"); value != "" {
+			var err error
+			if maxAge, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || maxAge < 0 {
+				return fmt.Errorf("cors: invalid Access-Control-Max-Age '%s'", value)
+			}
+		}
+		if err := m(maxAge); err != nil {
+			return fmt.Errorf("cors: max-age: %v", err)
+		}
+		return nil
+	}
+}
+
+// requestOrigin returns the Origin header of the request that the response is for
+func requestOrigin(response *http.Response) (string, error) {
+	if response.Request == nil {
+		return "", fmt.Errorf("cors: response has no associated request")
+	}
+	var origin = response.Request.Header.Get("Origin")
+	if origin == "" {
+		return "", fmt.Errorf("cors: request has no Origin header")
+	}
+	return origin, nil
+}
+
+// isPreflight returns true if the given request is a CORS preflight request
+func isPreflight(request *http.Request) bool {
+	return request.Method == http.MethodOptions && request.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// corsCheck implements the fetch spec's "CORS check"
+func corsCheck(response *http.Response, origin string, credentials bool) error {
+	var values = response.Header.Values("Access-Control-Allow-Origin")
+	switch {
+	case len(values) == 0:
+		return fmt.Errorf("cors: Access-Control-Allow-Origin not set")
+	case len(values) > 1:
+		return fmt.Errorf("cors: Access-Control-Allow-Origin set more than once")
+	case values[0] == "*" && !credentials:
+		return nil
+	case values[0] == "*":
+		return fmt.Errorf("cors: Access-Control-Allow-Origin cannot be '*' for requests with credentials")
+	case values[0] != origin:
+		return fmt.Errorf("cors: Access-Control-Allow-Origin (%s) not equal to origin (%s)", values[0], origin)
+	}
+
+	if credentials && response.Header.Get("Access-Control-Allow-Credentials") != "true" {
+		return fmt.Errorf("cors: Access-Control-Allow-Credentials not set to 'true'")
+	}
+	return nil
+}
+
+// safelisted are the methods that are always allowed by a preflight response
+var safelisted = map[string]bool{http.MethodGet: true, http.MethodHead: true, http.MethodPost: true}
+
+// preflightCheck implements the fetch spec's checks on a preflight response
+func preflightCheck(response *http.Response, origin string, credentials bool) error {
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return statusError(response, "an ok status")
+	}
+	if err := corsCheck(response, origin, credentials); err != nil {
+		return err
+	}
+
+	var methods = ParseList(response.Header.Values("Access-Control-Allow-Methods")...)
+	var method = response.Request.Header.Get("Access-Control-Request-Method")
+	if !safelisted[method] && !allows(methods, method, !credentials, true) {
+		return fmt.Errorf("cors: method %s not allowed by Access-Control-Allow-Methods %v", method, methods)
+	}
+
+	var headers = ParseList(response.Header.Values("Access-Control-Allow-Headers")...)
+	for _, header := range ParseList(response.Request.Header.Values("Access-Control-Request-Headers")...) {
+		// the wildcard never covers the Authorization header
+		var wildcard = !credentials && !strings.EqualFold(header, "Authorization")
+		if !allows(headers, header, wildcard, false) {
+			return fmt.Errorf("cors: header %s not allowed by Access-Control-Allow-Headers %v", header, headers)
+		}
+	}
+	return nil
+}
+
+// allows returns true if the given list contains the value (or the wildcard, if wildcard is true).
+// Methods are compared case-sensitively, whereas header names are compared case-insensitively.
+func allows(list []string, value string, wildcard, caseSensitive bool) bool {
+	for _, v := range list {
+		if (wildcard && v == "*") || v == value || (!caseSensitive && strings.EqualFold(v, value)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/builders"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// cors is a handler that allows requests from https://example.com
+var cors = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") != "https://example.com" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "https://example.com")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Vary", "Origin")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "PUT, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Token")
+		w.Header().Set("Access-Control-Max-Age", "600")
+	}
+	w.WriteHeader(http.StatusNoContent)
+})
+
+// receivedFor sets the request the response was received for
+func receivedFor(request *http.Request, response *http.Response) *http.Response {
+	response.Request = request
+	return response
+}
+
+func TestCorsWithExecutors(t *testing.T) {
+	var server = httptest.NewServer(cors)
+	defer server.Close()
+
+	for name, fn := range map[string]ExecFn{"handler": WithHandler(cors), "client": WithDefaultClient()} {
+		t.Run(name, func(t *testing.T) {
+			fn.MakeRequest(Options(server.URL),
+				builders.WithPreflight("https://example.com", http.MethodPut, "Content-Type", "X-Token"),
+			).ExpectIt(t, ToAllowPreflight(true), PreflightMaxAge(matchers.Equal(600)))
+
+			fn.MakeRequest(Options(server.URL),
+				builders.WithPreflight("https://example.com", http.MethodPatch),
+			).ExpectIt(t, Not(ToAllowPreflight(false)))
+
+			fn.MakeRequest(Options(server.URL),
+				builders.WithPreflight("https://evil.com", http.MethodPut),
+			).ExpectIt(t, ToRejectOrigin())
+
+			fn.MakeRequest(Get(server.URL), builders.WithOrigin("https://example.com")).
+				ExpectIt(t, ToAllowOrigin(true))
+		})
+	}
+}
+
+func TestCorsCheck(t *testing.T) {
+	var request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Origin", "https://example.com")
+
+	var wildcard = receivedFor(request, newResponse(http.StatusOK, http.Header{"Access-Control-Allow-Origin": {"*"}}, ""))
+	assert(t, ToAllowOrigin(false)(wildcard) == nil, "must allow wildcard without credentials")
+	assert(t, ToAllowOrigin(true)(wildcard) != nil, "must not allow wildcard with credentials")
+
+	var exact = receivedFor(request, newResponse(http.StatusOK, http.Header{"Access-Control-Allow-Origin": {"https://example.com"}}, ""))
+	assert(t, ToAllowOrigin(false)(exact) == nil, "must allow matching origin")
+	assert(t, ToAllowOrigin(true)(exact) != nil, "must require Access-Control-Allow-Credentials")
+	assert(t, ToRejectOrigin()(exact) != nil, "must fail if origin is allowed")
+
+	var other = receivedFor(request, newResponse(http.StatusOK, http.Header{"Access-Control-Allow-Origin": {"https://example.com/"}}, ""))
+	assert(t, ToAllowOrigin(false)(other) != nil, "must compare origin exactly")
+	assert(t, ToRejectOrigin()(other) == nil, "must pass if origin is not allowed")
+	assert(t, ToRejectOrigin()(receivedFor(request, newResponse(http.StatusOK, nil, ""))) == nil, "must pass if header is absent")
+
+	var noOrigin, _ = http.NewRequest(http.MethodGet, "/", nil)
+	assert(t, ToAllowOrigin(false)(receivedFor(noOrigin, newResponse(http.StatusOK, nil, ""))) != nil, "must fail without Origin")
+	assert(t, ToAllowOrigin(false)(receivedFor(nil, newResponse(http.StatusOK, nil, ""))) != nil, "must fail without request")
+}
+
+func TestPreflightCheck(t *testing.T) {
+	var request, _ = http.NewRequest(http.MethodOptions, "/", nil)
+	_ = builders.WithPreflight("https://example.com", http.MethodPut, "Authorization", "X-Token")(request)
+
+	var wildcard = receivedFor(request, newResponse(http.StatusOK, http.Header{
+		"Access-Control-Allow-Origin":  {"*"},
+		"Access-Control-Allow-Methods": {"*"},
+		"Access-Control-Allow-Headers": {"*"},
+	}, ""))
+	assert(t, ToAllowPreflight(false)(wildcard) != nil, "wildcard must not cover Authorization")
+
+	var explicit = receivedFor(request, newResponse(http.StatusOK, http.Header{
+		"Access-Control-Allow-Origin":  {"*"},
+		"Access-Control-Allow-Methods": {"put"},
+		"Access-Control-Allow-Headers": {"*, authorization"},
+	}, ""))
+	assert(t, ToAllowPreflight(false)(explicit) != nil, "must compare methods case-sensitively")
+
+	explicit.Header.Set("Access-Control-Allow-Methods", "PUT")
+	assert(t, ToAllowPreflight(false)(explicit) == nil, "must allow explicit Authorization")
+	assert(t, PreflightMaxAge(matchers.Equal(5))(explicit) == nil, "must default max-age to 5 seconds")
+
+	explicit.Header.Set("Access-Control-Max-Age", "-1")
+	assert(t, PreflightMaxAge(matchers.GreaterThan(-10))(explicit) != nil, "must fail on invalid max-age")
+
+	var put, _ = http.NewRequest(http.MethodOptions, "/", nil)
+	_ = builders.WithPreflight("https://example.com", http.MethodPut)(put)
+	var methods = receivedFor(put, newResponse(http.StatusOK, http.Header{
+		"Access-Control-Allow-Origin":  {"*"},
+		"Access-Control-Allow-Methods": {"*"},
+	}, ""))
+	assert(t, ToAllowPreflight(false)(methods) == nil, "wildcard must cover methods without credentials")
+
+	methods.Header.Set("Access-Control-Allow-Origin", "https://example.com")
+	methods.Header.Set("Access-Control-Allow-Credentials", "true")
+	assert(t, ToAllowPreflight(true)(methods) != nil, "wildcard must not cover methods with credentials")
+}
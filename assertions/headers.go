@@ -0,0 +1,103 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// WithHeaderValues returns an assertion which invokes the given handler with all the values of the header.
+// Unlike WithHeader(...), it allows checking multi-valued headers (like Set-Cookie, Vary or Link) and
+// distinguishing between an absent header (nil values) and an empty one (a single, empty value).
+func WithHeaderValues(name string, hn func([]string) error) httpx.Assertion {
+	return func(response *http.Response) error {
+		if err := hn(response.Header.Values(name)); err != nil {
+			return fmt.Errorf("header: %v", err)
+		}
+		return nil
+	}
+}
+
+// HeaderValuesThat returns an assertion that checks all the values of the header (as a []string) using the given matcher.
+//
+//  HeaderValuesThat("Vary", matchers.Contains("Accept-Encoding"))
+func HeaderValuesThat(name string, m matchers.Matcher) httpx.Assertion {
+	return WithHeaderValues(name, func(values []string) error {
+		if err := m(values); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// ToHaveHeader returns an assertion that checks whether any value of the header is exactly equal to the given value.
+func ToHaveHeader(name, value string) httpx.Assertion {
+	return anyHeaderValue(name, fmt.Sprintf("equal to '%s'", value), func(v string) bool { return v == value })
+}
+
+// HeaderContains returns an assertion that checks whether any value of the header contains the given substring.
+func HeaderContains(name, substr string) httpx.Assertion {
+	return anyHeaderValue(name, fmt.Sprintf("containing '%s'", substr), func(v string) bool { return strings.Contains(v, substr) })
+}
+
+// HeaderMatches returns an assertion that checks whether any value of the header matches the given regular expression.
+// Panics if the expression cannot be compiled.
+func HeaderMatches(name, expr string) httpx.Assertion {
+	var re = regexp.MustCompile(expr)
+	return anyHeaderValue(name, fmt.Sprintf("matching '%s'", expr), re.MatchString)
+}
+
+// NotHaveHeader returns an assertion that checks whether the header is absent from the response.
+func NotHaveHeader(name string) httpx.Assertion {
+	return WithHeaderValues(name, func(values []string) error {
+		return AssertThat(values == nil, "header with name '%s' found (%q)", name, values)
+	})
+}
+
+// WithHeaderList returns an assertion which parses the header as a comma-separated list (as defined by RFC 7230),
+// combining all of its values, and invokes the given handler with the list elements. Whitespace around elements
+// is trimmed, empty elements are dropped, and commas inside quoted strings are respected.
+//
+//  WithHeaderList("Cache-Control", func(directives []string) error { ... })
+func WithHeaderList(name string, hn func([]string) error) httpx.Assertion {
+	return WithHeaderValues(name, func(values []string) error {
+		return hn(ParseList(values...))
+	})
+}
+
+// WithTrailer returns an assertion which invokes the given handler with all the values of the given trailer.
+// Trailers are only available after the response body has been read, which MakeRequest(...) always does.
+func WithTrailer(name string, hn func([]string) error) httpx.Assertion {
+	return func(response *http.Response) error {
+		if err := hn(response.Trailer.Values(name)); err != nil {
+			return fmt.Errorf("trailer: %v", err)
+		}
+		return nil
+	}
+}
+
+// HaveTrailer returns an assertion that checks whether the response has the given trailer.
+func HaveTrailer(name string) httpx.Assertion {
+	return WithTrailer(name, func(values []string) error {
+		return AssertThat(values != nil, "trailer with name '%s' not found", name)
+	})
+}
+
+// anyHeaderValue returns an assertion that checks whether any value of the header satisfies the predicate
+func anyHeaderValue(name, description string, predicate func(string) bool) httpx.Assertion {
+	return WithHeaderValues(name, func(values []string) error {
+		for _, v := range values {
+			if predicate(v) {
+				return nil
+			}
+		}
+		if values == nil {
+			return fmt.Errorf("header with name '%s' not found", name)
+		}
+		return fmt.Errorf("header '%s' has no value %s (%q)", name, description, values)
+	})
+}
@@ -0,0 +1,75 @@
+package assertions_test
+
+import (
+	"errors"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// varied is the header of the response used by header tests; X-Checksum is sent as a trailer
+var varied = http.Header{
+	"Vary":       {"Accept", "Accept-Encoding, Origin"},
+	"X-Empty":    {""},
+	"Trailer":    {"X-Checksum"},
+	"X-Checksum": {"abc"},
+}
+
+func TestWithHeaderValues(t *testing.T) {
+	var resp = newResponse(http.StatusOK, varied, "")
+
+	var values []string
+	assert(t, WithHeaderValues("vary", func(v []string) error { values = v; return nil })(resp) == nil, "must not fail")
+	assert(t, len(values) == 2, "must pass all values")
+	assert(t, WithHeaderValues("x-missing", func(v []string) error {
+		if v != nil {
+			return errors.New("must be nil")
+		}
+		return nil
+	})(resp) == nil, "must pass nil values if header is absent")
+	assert(t, WithHeaderValues("x-empty", func(v []string) error {
+		if len(v) != 1 || v[0] != "" {
+			return errors.New("must be single empty value")
+		}
+		return nil
+	})(resp) == nil, "must pass empty value if header is empty")
+
+	assert(t, HeaderValuesThat("vary", matchers.Contains("Accept"))(resp) == nil, "must match all values")
+	assert(t, HeaderValuesThat("vary", matchers.Len(3))(resp) != nil, "must fail if matcher fails")
+}
+
+func TestHeaderValueAssertions(t *testing.T) {
+	var resp = newResponse(http.StatusOK, varied, "")
+
+	assert(t, ToHaveHeader("vary", "Accept")(resp) == nil, "must match any exact value")
+	assert(t, ToHaveHeader("vary", "Origin")(resp) != nil, "must not match partial value")
+	assert(t, HeaderContains("vary", "Origin")(resp) == nil, "must match any value containing substring")
+	assert(t, HeaderContains("x-missing", "")(resp) != nil, "must fail if header is absent")
+	assert(t, HeaderMatches("vary", `^Accept-\w+`)(resp) == nil, "must match any value matching regexp")
+	assert(t, HeaderMatches("vary", `^Origin`)(resp) != nil, "must fail if no value matches regexp")
+	assert(t, NotHaveHeader("x-missing")(resp) == nil, "must pass if header is absent")
+	assert(t, NotHaveHeader("x-empty")(resp) != nil, "must fail if header is present, even if empty")
+}
+
+func TestWithHeaderList(t *testing.T) {
+	var resp = newResponse(http.StatusOK, varied, "")
+
+	var list []string
+	_ = WithHeaderList("vary", func(l []string) error { list = l; return nil })(resp)
+	assert(t, reflect.DeepEqual(list, []string{"Accept", "Accept-Encoding", "Origin"}), "must parse list: %q", list)
+}
+
+func TestTrailers(t *testing.T) {
+	var resp = newResponse(http.StatusOK, varied, "")
+
+	assert(t, HaveTrailer("x-checksum")(resp) == nil, "must find trailer")
+	assert(t, HaveTrailer("x-missing")(resp) != nil, "must fail if trailer is absent")
+	assert(t, WithTrailer("x-checksum", func(v []string) error {
+		if len(v) != 1 || v[0] != "abc" {
+			return errors.New("unexpected value")
+		}
+		return nil
+	})(resp) == nil, "must pass trailer values")
+}
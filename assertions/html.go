@@ -0,0 +1,195 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/dom"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"golang.org/x/net/html"
+	"net/http"
+	"strings"
+)
+
+// The assertions in this file parse the response body as html and select elements using CSS selectors
+// (see dom.CompileSelector(...) for the supported syntax). Like XPath(...), assertions that check values
+// give the matcher a string if a single element is selected, or a []string if multiple elements are selected.
+
+// ToHaveElement returns an assertion that checks whether at least one element matches the given selector.
+func ToHaveElement(selector string) httpx.Assertion {
+	return selectHtml(selector, func(_ *http.Response, nodes []*html.Node) error {
+		return AssertThat(len(nodes) > 0, "no element matches")
+	})
+}
+
+// ElementCount returns an assertion that checks the number of elements matching the given selector using the given matcher.
+//
+//  ElementCount("table#users > tbody > tr", matchers.Equal(10))
+func ElementCount(selector string, m matchers.Matcher) httpx.Assertion {
+	return selectHtml(selector, func(_ *http.Response, nodes []*html.Node) error {
+		return m(len(nodes))
+	})
+}
+
+// ElementText returns an assertion that checks the text content (with whitespace collapsed, see dom.Text(...))
+// of the elements matching the given selector using the given matcher.
+//
+//  ElementText("h1", matchers.Equal("Welcome, john"))
+func ElementText(selector string, m matchers.Matcher) httpx.Assertion {
+	return selectHtml(selector, func(_ *http.Response, nodes []*html.Node) error {
+		var values []string
+		for _, n := range nodes {
+			values = append(values, dom.Text(n))
+		}
+		return matchValues(values, m)
+	})
+}
+
+// ElementAttr returns an assertion that checks the given attribute of the elements matching the given selector
+// using the given matcher. Elements without the attribute are ignored.
+//
+//  ElementAttr("img.avatar", "alt", matchers.Not(matchers.Empty()))
+func ElementAttr(selector, attr string, m matchers.Matcher) httpx.Assertion {
+	return selectHtml(selector, func(_ *http.Response, nodes []*html.Node) error {
+		var values []string
+		for _, n := range nodes {
+			if v, ok := dom.Attr(n, attr); ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 && len(nodes) > 0 {
+			return fmt.Errorf("no element has attribute '%s'", attr)
+		}
+		return matchValues(values, m)
+	})
+}
+
+// FormField returns an assertion that checks the value(s) a browser would submit for the named field, of the first form
+// matching the given selector, using the given matcher (see dom.FormValues(...)). It fails if the field has no value,
+// say, because it doesn't exist or is an unchecked checkbox (use ToHaveElement("form input[name=x]:checked") instead).
+//
+//  FormField("form#profile", "email", matchers.Equal("john@example.com"))
+func FormField(form, name string, m matchers.Matcher) httpx.Assertion {
+	return selectHtml(form, func(_ *http.Response, nodes []*html.Node) error {
+		if len(nodes) == 0 {
+			return fmt.Errorf("no form matches")
+		}
+		var values = dom.FormValues(nodes[0])[name]
+		if len(values) == 0 {
+			return fmt.Errorf("field '%s' not found or has no value", name)
+		}
+		if err := matchValues(values, m); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// ToHaveLink returns an assertion that checks whether the page has a link (an <a> element) with the given text that
+// points to the given target. Both the link's href and the target are resolved relative to the request url before
+// being compared, and so relative targets (like "/logout") can be used. An empty text matches any link text.
+func ToHaveLink(text, target string) httpx.Assertion {
+	return selectHtml("a[href]", func(response *http.Response, nodes []*html.Node) error {
+		var expected, err = resolve(response, target)
+		if err != nil {
+			return fmt.Errorf("invalid target '%s': %v", target, err)
+		}
+
+		for _, n := range nodes {
+			var href, _ = dom.Attr(n, "href")
+			if actual, err := resolve(response, strings.TrimSpace(href)); err == nil && actual.String() == expected.String() {
+				if text == "" || dom.Text(n) == text {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("no link with text '%s' to %s", text, expected)
+	})
+}
+
+// Capture returns an assertion that stores the given attribute (or the text content, if attr is empty) of the first element
+// matching the given selector into dst, failing if there's no such element. Use it to carry values (like ids or tokens)
+// from one response into the next request, like,
+//
+//  var id string
+//  MakeRequest(...).ExpectIt(t, Capture("tr.user:first-child", "data-id", &id))
+//  MakeRequest(Delete("/users/" + id)).ExpectIt(t, ...)
+func Capture(selector, attr string, dst *string) httpx.Assertion {
+	return selectHtml(selector, func(_ *http.Response, nodes []*html.Node) error {
+		if len(nodes) == 0 {
+			return fmt.Errorf("no element matches")
+		}
+		if attr == "" {
+			*dst = dom.Text(nodes[0])
+			return nil
+		}
+		var v, ok = dom.Attr(nodes[0], attr)
+		if !ok {
+			return fmt.Errorf("element has no attribute '%s'", attr)
+		}
+		*dst = v
+		return nil
+	})
+}
+
+// csrfSelector matches the hidden inputs and meta tags commonly used by web frameworks to embed CSRF tokens
+const csrfSelector = `input[type=hidden][name=csrf_token], input[type=hidden][name=_csrf], input[type=hidden][name=csrfmiddlewaretoken], ` +
+	`input[type=hidden][name=authenticity_token], input[type=hidden][name=__RequestVerificationToken], ` +
+	`input[type=hidden][name="gorilla.csrf.Token"], meta[name=csrf-token], meta[name=csrf_token]`
+
+// CaptureCSRFToken returns an assertion that finds the CSRF token embedded in the page (using the field or meta tag names
+// used by common web frameworks) and stores it into dst, so that it can be sent along with the next request, like,
+//
+//  var token string
+//  MakeRequest(Get("/login")).ExpectIt(t, CaptureCSRFToken(&token))
+//  MakeRequest(Post("/login", nil), WithForm(url.Values{"csrf_token": {token}, ...}))
+//
+// Use Capture(...) if the token is embedded in some other way.
+func CaptureCSRFToken(dst *string) httpx.Assertion {
+	return selectHtml(csrfSelector, func(_ *http.Response, nodes []*html.Node) error {
+		for _, n := range nodes {
+			var attr = "value"
+			if n.Data == "meta" {
+				attr = "content"
+			}
+			if v, _ := dom.Attr(n, attr); v != "" {
+				*dst = v
+				return nil
+			}
+		}
+		return fmt.Errorf("no csrf token found")
+	})
+}
+
+// selectHtml returns an assertion that parses the response body as html and invokes fn with the elements
+// matching the given selector
+func selectHtml(selector string, fn func(*http.Response, []*html.Node) error) httpx.Assertion {
+	var sel, err = dom.CompileSelector(selector)
+	if err != nil {
+		return failed(fmt.Errorf("html: %v", err))
+	}
+
+	return func(response *http.Response) (err error) {
+		defer checkClose(response.Body, &err)
+
+		var doc *html.Node
+		if doc, err = html.Parse(response.Body); err != nil {
+			return fmt.Errorf("html: failed to parse response body: %v", err)
+		}
+		if err = fn(response, sel.Select(doc)); err != nil {
+			return fmt.Errorf("html: %s: %v", selector, err)
+		}
+		return nil
+	}
+}
+
+// matchValues invokes the matcher with the single value, or all the values, failing if there are none
+func matchValues(values []string, m matchers.Matcher) error {
+	switch len(values) {
+	case 0:
+		return fmt.Errorf("no element matches")
+	case 1:
+		return m(values[0])
+	}
+	return m(values)
+}
@@ -0,0 +1,101 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/builders"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/matchers"
+	"html/template"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+var profile = template.Must(template.New("profile").Parse(`<!doctype html>
+<html>
+<head><meta name="csrf-token" content="{{ .Token }}"><title>Profile</title></head>
+<body>
+  <h1>  Welcome,
+    {{ .Name }}</h1>
+  <ul class="users">{{ range .Users }}<li data-id="{{ .ID }}">{{ .Name }}</li>{{ end }}</ul>
+  <a href="/logout">Log out</a> <a href="https://example.com/help">Help</a>
+  <form id="profile" method="post">
+    <input type="hidden" name="csrf_token" value="{{ .Token }}">
+    <input name="name" value="{{ .Name }}">
+    <input type="checkbox" name="newsletter">
+  </form>
+</body>
+</html>`))
+
+type profileUser struct {
+	ID   int
+	Name string
+}
+
+// profileHandler renders the profile page on GET, and accepts the form on POST only if it has a valid csrf token
+var profileHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		if r.PostFormValue("csrf_token") != "s3cr3t" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+		return
+	}
+	_ = profile.Execute(w, map[string]interface{}{
+		"Token": "s3cr3t",
+		"Name":  "john <doe>",
+		"Users": []profileUser{{1, "john"}, {2, "jane"}},
+	})
+})
+
+func TestHtmlAssertions(t *testing.T) {
+	var fn = WithHandler(profileHandler)
+	fn.MakeRequest(Get("http://example.com/profile")).ExpectIt(t,
+		ToHaveElement("form#profile"),
+		ElementCount("ul.users > li", matchers.Equal(2)),
+		ElementText("h1", matchers.Equal("Welcome, john <doe>")),
+		ElementText("ul.users li", matchers.Equal([]string{"john", "jane"})),
+		ElementAttr("li:last-child", "data-id", matchers.Equal("2")),
+		FormField("form#profile", "name", matchers.Equal("john <doe>")),
+		ToHaveLink("Log out", "/logout"),
+		ToHaveLink("", "https://example.com/help"),
+	)
+
+	var r = make(reporter)
+	fn.MakeRequest(Get("http://example.com/profile")).ExpectIt(r,
+		ToHaveElement("table"),
+		ElementCount("li", matchers.Equal(3)),
+		ElementText("h2", matchers.Empty()),
+		ElementAttr("h1", "id", matchers.Empty()),
+		FormField("form#profile", "newsletter", matchers.Empty()),
+		FormField("form#missing", "name", matchers.Empty()),
+		ToHaveLink("Log out", "/help"),
+		ToHaveElement("li:hover"),
+	)
+	assert(t, r["Errorf"] == 8, "all assertions must fail, got %d", r["Errorf"])
+}
+
+func TestCapture(t *testing.T) {
+	var fn = WithHandler(profileHandler)
+
+	var token, id, name string
+	fn.MakeRequest(Get("http://example.com/profile")).ExpectIt(t,
+		CaptureCSRFToken(&token),
+		Capture("ul.users li:first-child", "data-id", &id),
+		Capture("ul.users li:first-child", "", &name),
+	)
+	assert(t, token == "s3cr3t" && id == "1" && name == "john", "must capture values: %q %q %q", token, id, name)
+
+	fn.MakeRequest(Post("http://example.com/profile", nil), builders.WithForm(url.Values{"csrf_token": {token}})).
+		ExpectIt(t, ToHaveStatus(http.StatusOK))
+	fn.MakeRequest(Post("http://example.com/profile", nil), builders.WithForm(url.Values{})).
+		ExpectIt(t, ToHaveStatus(http.StatusForbidden))
+
+	var r = make(reporter)
+	fn.MakeRequest(Get("http://example.com/profile")).ExpectIt(r,
+		Capture("table", "", &name),
+		Capture("h1", "id", &name),
+	)
+	WithHandlerFn(func(w http.ResponseWriter, r *http.Request) {}).MakeRequest(Get("/")).ExpectIt(r, CaptureCSRFToken(&token))
+	assert(t, r["Errorf"] == 3, "must fail if nothing is captured")
+}
@@ -0,0 +1,59 @@
+package assertions
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+)
+
+// HeaderThat returns an assertion that checks the (first) value of the given header using the given matcher.
+func HeaderThat(name string, m matchers.Matcher) httpx.Assertion {
+	return WithHeader(name, func(value string) error {
+		if err := m(value); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// CookieThat returns an assertion that checks the value of the given cookie using the given matcher.
+// The assertion fails if the cookie is not set.
+func CookieThat(name string, m matchers.Matcher) httpx.Assertion {
+	return WithCookie(name, func(cookie *http.Cookie) error {
+		if cookie == nil {
+			return fmt.Errorf("cookie with name '%s' not set", name)
+		}
+		if err := m(cookie.Value); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	})
+}
+
+// JsonPath returns an assertion that decodes the response body as json, looks up the value at the given path
+// (see helpers.LookupJsonPath(...) for the syntax) and checks it using the given matcher. Json numbers are
+// decoded as float64, but matchers compare numbers by value and so Equal(1) works as expected.
+//
+//  JsonPath("data.users[0].name", matchers.Equal("john"))
+func JsonPath(path string, m matchers.Matcher) httpx.Assertion {
+	return func(response *http.Response) (err error) {
+		defer checkClose(response.Body, &err)
+
+		var doc interface{}
+		if err := json.NewDecoder(response.Body).Decode(&doc); err != nil {
+			return fmt.Errorf("json: failed to decode response body: %v", err)
+		}
+
+		var value interface{}
+		if value, err = LookupJsonPath(doc, path); err != nil {
+			return fmt.Errorf("json: %v", err)
+		}
+		if err = m(value); err != nil {
+			return fmt.Errorf("json: %s: %v", path, err)
+		}
+		return nil
+	}
+}
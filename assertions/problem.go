@@ -0,0 +1,167 @@
+package assertions
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"math"
+	"net/http"
+	"net/url"
+)
+
+// ProblemMediaType is the media type of problem details documents, as defined by RFC 7807
+const ProblemMediaType = "application/problem+json"
+
+// Problem is a decoded problem details document (see RFC 7807).
+type Problem struct {
+	// Type is a URI reference that identifies the problem type. Defaults to "about:blank" if not set.
+	Type string
+
+	// Title is a short, human-readable summary of the problem type
+	Title string
+
+	// Status is the HTTP status code, or zero if not set
+	Status int
+
+	// Detail is a human-readable explanation specific to this occurrence of the problem
+	Detail string
+
+	// Instance is a URI reference that identifies the specific occurrence of the problem
+	Instance string
+
+	// Extensions contains the extension members (ie. all members other than the ones above)
+	Extensions map[string]interface{}
+}
+
+// ProblemCheck defines a function that checks a decoded problem details document.
+// Custom checks can be written inline, like,
+//
+//  ToBeProblem(func(p *Problem) error { return AssertThat(p.Extensions["balance"] != nil, "balance not set") })
+type ProblemCheck func(*Problem) error
+
+// ToBeProblem returns an assertion that checks whether the response is a valid problem details document, and passes
+// all the given checks. As per RFC 7807, the response must,
+//
+//  - have an error (4xx or 5xx) status
+//  - have the application/problem+json media type
+//  - have a json object as its body, where type, title, detail and instance (if present) are strings,
+//    type and instance are valid URI references, and status (if present) is equal to the response status
+func ToBeProblem(checks ...ProblemCheck) httpx.Assertion {
+	return func(response *http.Response) (err error) {
+		defer checkClose(response.Body, &err)
+
+		if response.StatusCode < 400 {
+			return statusError(response, "an error (4xx or 5xx)")
+		}
+		if err := ToHaveContentType(ProblemMediaType)(response); err != nil {
+			return fmt.Errorf("problem: %v", err)
+		}
+
+		var members map[string]interface{}
+		if err := json.NewDecoder(response.Body).Decode(&members); err != nil {
+			return fmt.Errorf("problem: failed to decode response body as a json object: %v", err)
+		}
+
+		var problem *Problem
+		if problem, err = decodeProblem(members); err != nil {
+			return fmt.Errorf("problem: %v", err)
+		}
+		if problem.Status != 0 && problem.Status != response.StatusCode {
+			return fmt.Errorf("problem: status member (%d) not equal to response status (%d)", problem.Status, response.StatusCode)
+		}
+
+		for _, fn := range checks {
+			if err := fn(problem); err != nil {
+				return fmt.Errorf("problem: %v", err)
+			}
+		}
+		return nil
+	}
+}
+
+// ProblemType returns a ProblemCheck that checks whether the problem type is equal to the given URI.
+func ProblemType(uri string) ProblemCheck {
+	return func(p *Problem) error {
+		return AssertThat(p.Type == uri, "type (%s) not equal to expected type (%s)", p.Type, uri)
+	}
+}
+
+// ProblemTitle returns a ProblemCheck that checks the problem title using the given matcher.
+func ProblemTitle(m matchers.Matcher) ProblemCheck {
+	return problemMember("title", func(p *Problem) interface{} { return p.Title }, m)
+}
+
+// ProblemDetail returns a ProblemCheck that checks the problem detail using the given matcher.
+func ProblemDetail(m matchers.Matcher) ProblemCheck {
+	return problemMember("detail", func(p *Problem) interface{} { return p.Detail }, m)
+}
+
+// ProblemInstance returns a ProblemCheck that checks the problem instance using the given matcher.
+func ProblemInstance(m matchers.Matcher) ProblemCheck {
+	return problemMember("instance", func(p *Problem) interface{} { return p.Instance }, m)
+}
+
+// ProblemExtension returns a ProblemCheck that looks up the value at the given path within the extension members
+// (see helpers.LookupJsonPath(...) for the syntax) and checks it using the given matcher.
+//
+//  ProblemExtension("invalid-params[0].name", matchers.Equal("age"))
+func ProblemExtension(path string, m matchers.Matcher) ProblemCheck {
+	return func(p *Problem) error {
+		var value, err = LookupJsonPath(p.Extensions, path)
+		if err != nil {
+			return err
+		}
+		if err = m(value); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		return nil
+	}
+}
+
+// problemMember returns a ProblemCheck that checks the member returned by get using the given matcher
+func problemMember(name string, get func(*Problem) interface{}, m matchers.Matcher) ProblemCheck {
+	return func(p *Problem) error {
+		if err := m(get(p)); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	}
+}
+
+// decodeProblem validates and decodes the members of a problem details document
+func decodeProblem(members map[string]interface{}) (*Problem, error) {
+	if members == nil { // a json null decodes into a nil map
+		return nil, fmt.Errorf("body must be a json object, got null")
+	}
+
+	var problem = &Problem{Type: "about:blank", Extensions: make(map[string]interface{})}
+	var fields = map[string]*string{"type": &problem.Type, "title": &problem.Title, "detail": &problem.Detail, "instance": &problem.Instance}
+
+	for name, value := range members {
+		if field, ok := fields[name]; ok {
+			var s, isString = value.(string)
+			if !isString {
+				return nil, fmt.Errorf("%s member must be a string, got %v", name, value)
+			}
+			*field = s
+		} else if name == "status" {
+			var n, isNumber = value.(float64)
+			if !isNumber || n != math.Trunc(n) || n < 100 || n > 599 {
+				return nil, fmt.Errorf("status member must be an http status code, got %v", value)
+			}
+			problem.Status = int(n)
+		} else {
+			problem.Extensions[name] = value
+		}
+	}
+
+	if _, err := url.Parse(problem.Type); err != nil {
+		return nil, fmt.Errorf("type member must be a URI reference, got '%s'", problem.Type)
+	}
+	if _, err := url.Parse(problem.Instance); err != nil {
+		return nil, fmt.Errorf("instance member must be a URI reference, got '%s'", problem.Instance)
+	}
+	return problem, nil
+}
@@ -0,0 +1,58 @@
+package assertions_test
+
+import (
+	"errors"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"testing"
+)
+
+const outOfCredit = `{
+	"type": "https://example.com/probs/out-of-credit",
+	"title": "You do not have enough credit.",
+	"status": 403,
+	"detail": "Your current balance is 30, but that costs 50.",
+	"instance": "/account/12345/msgs/abc",
+	"balance": 30,
+	"accounts": ["/account/12345", "/account/67890"]
+}`
+
+func TestToBeProblem(t *testing.T) {
+	var problem = func() *http.Response {
+		return newResponse(http.StatusForbidden, contentType(ProblemMediaType), outOfCredit)
+	}
+
+	assert(t, ToBeProblem()(problem()) == nil, "must pass for valid problem")
+	assert(t, ToBeProblem(
+		ProblemType("https://example.com/probs/out-of-credit"),
+		ProblemTitle(matchers.HasPrefix("You do not")),
+		ProblemDetail(matchers.Contains("balance is 30")),
+		ProblemInstance(matchers.Equal("/account/12345/msgs/abc")),
+		ProblemExtension("balance", matchers.Equal(30)),
+		ProblemExtension("accounts[1]", matchers.HasSuffix("67890")),
+	)(problem()) == nil, "must pass all checks")
+
+	assert(t, ToBeProblem(ProblemType("about:blank"))(problem()) != nil, "must fail if type differs")
+	assert(t, ToBeProblem(ProblemExtension("title", matchers.Nil()))(problem()) != nil, "must not look up standard members as extensions")
+	assert(t, ToBeProblem(func(*Problem) error { return errors.New("custom") })(problem()) != nil, "must run custom checks")
+
+	var blank = newResponse(http.StatusNotFound, contentType("application/problem+json; charset=utf-8"), `{"title": "Not Found"}`)
+	assert(t, ToBeProblem(ProblemType("about:blank"))(blank) == nil, "must default type to about:blank")
+}
+
+func TestToBeProblemValidation(t *testing.T) {
+	var cases = map[string]*http.Response{
+		"success status":    newResponse(http.StatusOK, contentType(ProblemMediaType), `{}`),
+		"json media type":   newResponse(http.StatusBadRequest, contentType("application/json"), `{}`),
+		"non-object body":   newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `[]`),
+		"null body":         newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `null`),
+		"status mismatch":   newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `{"status": 404}`),
+		"fractional status": newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `{"status": 400.5}`),
+		"non-string title":  newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `{"title": 1}`),
+		"invalid type uri":  newResponse(http.StatusBadRequest, contentType(ProblemMediaType), `{"type": "http://[::1"}`),
+	}
+	for name, response := range cases {
+		assert(t, ToBeProblem()(response) != nil, "must fail for %s", name)
+	}
+}
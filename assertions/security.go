@@ -0,0 +1,457 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityProfile configures the checks run by SecurityHeaders(...). Zero values disable the corresponding check,
+// so a profile can be built from scratch, or derived from one of the bundled profiles, like,
+//
+//  var profile = StrictSecurityProfile()
+//  profile.CSP = append(profile.CSP, CSPDirective("img-src", matchers.Contains("'self'")))
+//  ...
+//  MakeRequest(...).ExpectIt(t, SecurityHeaders(profile))
+type SecurityProfile struct {
+	// HSTSMaxAge is the minimum max-age that Strict-Transport-Security must have,
+	// and HSTSIncludeSubDomains requires it to have the includeSubDomains directive
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubDomains bool
+
+	// RequireCSP requires a Content-Security-Policy to be set, that passes all the checks in CSP
+	RequireCSP bool
+	CSP        []CSPCheck
+
+	// NoSniff requires X-Content-Type-Options to be nosniff
+	NoSniff bool
+
+	// DenyFraming requires framing to be restricted using either X-Frame-Options or CSP frame-ancestors
+	DenyFraming bool
+
+	// ReferrerPolicies is the list of accepted Referrer-Policy values
+	ReferrerPolicies []string
+
+	// RequirePermissionsPolicy requires a Permissions-Policy to be set, that declares all the given features
+	RequirePermissionsPolicy bool
+	PermissionsPolicy        []string
+
+	// HideServerDetails requires that no version information is disclosed (see NotDiscloseServerDetails())
+	HideServerDetails bool
+}
+
+// BaselineSecurityProfile returns a profile with the checks that any response served over https should pass.
+func BaselineSecurityProfile() SecurityProfile {
+	return SecurityProfile{
+		HSTSMaxAge:        180 * 24 * time.Hour,
+		NoSniff:           true,
+		DenyFraming:       true,
+		ReferrerPolicies:  SafeReferrerPolicies(),
+		HideServerDetails: true,
+	}
+}
+
+// StrictSecurityProfile returns the baseline profile, that additionally requires HSTS to cover sub-domains
+// (with a max-age of at least a year), a Content-Security-Policy without unsafe script sources, and a Permissions-Policy.
+func StrictSecurityProfile() SecurityProfile {
+	var profile = BaselineSecurityProfile()
+	profile.HSTSMaxAge = 365 * 24 * time.Hour
+	profile.HSTSIncludeSubDomains = true
+	profile.RequireCSP = true
+	profile.CSP = []CSPCheck{CSPNoUnsafeInline(), CSPNoUnsafeEval(), AnyCSP(func(policy CSP) error {
+		// blocking plugins is a restriction, and so any policy can enforce it
+		return CSPDirective("object-src", matchers.ElementsMatch([]string{"'none'"}))([]CSP{policy})
+	})}
+	profile.RequirePermissionsPolicy = true
+	return profile
+}
+
+// SecurityHeaders returns an assertion that checks the response against the given profile.
+// All the checks are evaluated (using AllOf(...)) so that every missing header is reported at once.
+func SecurityHeaders(profile SecurityProfile) httpx.Assertion {
+	var assertions []httpx.Assertion
+	if profile.HSTSMaxAge > 0 || profile.HSTSIncludeSubDomains {
+		assertions = append(assertions, ToHaveHSTS(profile.HSTSMaxAge, profile.HSTSIncludeSubDomains))
+	}
+	if profile.RequireCSP {
+		assertions = append(assertions, ToHaveCSP(profile.CSP...))
+	}
+	if profile.NoSniff {
+		assertions = append(assertions, ToHaveNoSniff())
+	}
+	if profile.DenyFraming {
+		assertions = append(assertions, ToDenyFraming())
+	}
+	if len(profile.ReferrerPolicies) > 0 {
+		assertions = append(assertions, ToHaveReferrerPolicy(profile.ReferrerPolicies...))
+	}
+	if profile.RequirePermissionsPolicy {
+		assertions = append(assertions, ToHavePermissionsPolicy(profile.PermissionsPolicy...))
+	}
+	if profile.HideServerDetails {
+		assertions = append(assertions, NotDiscloseServerDetails())
+	}
+	return AllOf(assertions...)
+}
+
+// ToHaveHSTS returns an assertion that checks whether the response has a Strict-Transport-Security header
+// with a max-age of at least the given duration, and (optionally) the includeSubDomains directive.
+func ToHaveHSTS(maxAge time.Duration, includeSubDomains bool) httpx.Assertion {
+	return func(response *http.Response) error {
+		var value = response.Header.Get("Strict-Transport-Security")
+		if value == "" {
+			return fmt.Errorf("security: Strict-Transport-Security not set")
+		}
+
+		var age = -1
+		var subDomains bool
+		for _, directive := range strings.Split(value, ";") {
+			var name, arg = splitDirective(directive, "=")
+			switch strings.ToLower(name) {
+			case "max-age":
+				var err error
+				if age, err = strconv.Atoi(strings.Trim(arg, `"`)); err != nil || age < 0 {
+					return fmt.Errorf("security: Strict-Transport-Security has invalid max-age '%s'", arg)
+				}
+			case "includesubdomains":
+				subDomains = true
+			}
+		}
+
+		if age < 0 {
+			return fmt.Errorf("security: Strict-Transport-Security has no max-age")
+		}
+		if time.Duration(age)*time.Second < maxAge {
+			return fmt.Errorf("security: Strict-Transport-Security max-age (%ds) less than expected (%ds)", age, int(maxAge.Seconds()))
+		}
+		return AssertThat(subDomains || !includeSubDomains, "security: Strict-Transport-Security does not include sub-domains")
+	}
+}
+
+// ToHaveNoSniff returns an assertion that checks whether X-Content-Type-Options is set to nosniff.
+func ToHaveNoSniff() httpx.Assertion {
+	return func(response *http.Response) error {
+		var value = response.Header.Get("X-Content-Type-Options")
+		return AssertThat(strings.EqualFold(strings.TrimSpace(value), "nosniff"),
+			"security: X-Content-Type-Options (%s) not equal to nosniff", value)
+	}
+}
+
+// ToDenyFraming returns an assertion that checks whether the response restricts being framed by other origins, using either
+// X-Frame-Options (DENY or SAMEORIGIN) or the frame-ancestors directive of Content-Security-Policy (which supersedes it).
+//
+// As browsers enforce every policy, framing is restricted if any policy sets frame-ancestors without allowing
+// arbitrary origins, ie. without a wildcard (like * or https://*) or a scheme-only source (like https:).
+func ToDenyFraming() httpx.Assertion {
+	return func(response *http.Response) error {
+		var permissive []string
+		for _, policy := range ParseCSP(response.Header.Values("Content-Security-Policy")...) {
+			if sources, ok := policy["frame-ancestors"]; ok {
+				var source = anyOrigin(sources)
+				if source == "" {
+					return nil
+				}
+				permissive = append(permissive, source)
+			}
+		}
+		if len(permissive) > 0 {
+			return fmt.Errorf("security: Content-Security-Policy frame-ancestors allows any origin (%s)", strings.Join(permissive, ", "))
+		}
+
+		switch value := strings.ToUpper(strings.TrimSpace(response.Header.Get("X-Frame-Options"))); value {
+		case "DENY", "SAMEORIGIN":
+			return nil
+		case "":
+			return fmt.Errorf("security: neither X-Frame-Options nor Content-Security-Policy frame-ancestors set")
+		default:
+			return fmt.Errorf("security: X-Frame-Options (%s) not one of DENY or SAMEORIGIN", value)
+		}
+	}
+}
+
+// schemeSource matches scheme-only CSP sources, like https: or data:
+var schemeSource = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:$`)
+
+// anyOrigin returns the first of the given CSP sources that matches arbitrary origins (if any)
+func anyOrigin(sources []string) string {
+	for _, source := range sources {
+		if schemeSource.MatchString(source) {
+			return source
+		}
+		var host = source
+		if i := strings.Index(host, "://"); i >= 0 {
+			host = host[i+3:]
+		}
+		if i := strings.IndexAny(host, ":/"); i >= 0 {
+			host = host[:i]
+		}
+		if host == "*" {
+			return source
+		}
+	}
+	return ""
+}
+
+// SafeReferrerPolicies returns the referrer policies that do not leak the full url to other origins.
+func SafeReferrerPolicies() []string {
+	return []string{"no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin"}
+}
+
+// ToHaveReferrerPolicy returns an assertion that checks whether Referrer-Policy is set to one of the given policies.
+// As browsers do, when the header lists multiple policies the last one they recognise is used.
+func ToHaveReferrerPolicy(policies ...string) httpx.Assertion {
+	var known = map[string]bool{
+		"no-referrer": true, "no-referrer-when-downgrade": true, "origin": true, "origin-when-cross-origin": true,
+		"same-origin": true, "strict-origin": true, "strict-origin-when-cross-origin": true, "unsafe-url": true,
+	}
+
+	return func(response *http.Response) error {
+		var policy string
+		for _, p := range ParseList(response.Header.Values("Referrer-Policy")...) {
+			if p = strings.ToLower(p); known[p] {
+				policy = p
+			}
+		}
+		if policy == "" {
+			return fmt.Errorf("security: Referrer-Policy not set")
+		}
+
+		for _, p := range policies {
+			if strings.EqualFold(p, policy) {
+				return nil
+			}
+		}
+		return fmt.Errorf("security: Referrer-Policy (%s) not one of %v", policy, policies)
+	}
+}
+
+// ToHavePermissionsPolicy returns an assertion that checks whether a Permissions-Policy is set,
+// and that it declares an allowlist for each of the given features (like camera or geolocation).
+func ToHavePermissionsPolicy(features ...string) httpx.Assertion {
+	return func(response *http.Response) error {
+		var values = response.Header.Values("Permissions-Policy")
+		if len(values) == 0 {
+			return fmt.Errorf("security: Permissions-Policy not set")
+		}
+
+		var declared = make(map[string]bool)
+		for _, element := range ParseList(values...) {
+			var name, allowlist = splitDirective(element, "=")
+			if allowlist == "" {
+				return fmt.Errorf("security: Permissions-Policy has malformed entry '%s'", element)
+			}
+			declared[strings.ToLower(name)] = true
+		}
+
+		for _, feature := range features {
+			if !declared[strings.ToLower(feature)] {
+				return fmt.Errorf("security: Permissions-Policy does not declare '%s'", feature)
+			}
+		}
+		return nil
+	}
+}
+
+// disclosing are the headers commonly used to disclose the server's technology stack
+var disclosing = []string{"X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version", "X-Runtime", "X-Generator"}
+
+// version matches product tokens with a version (like nginx/1.19.0 or PHP 7.4)
+var version = regexp.MustCompile(`/\s*v?\d|\b\d+\.\d+`)
+
+// NotDiscloseServerDetails returns an assertion that checks that the Server header (if any) does not contain
+// a version number, and that headers disclosing the server's technology stack (like X-Powered-By) are absent.
+func NotDiscloseServerDetails() httpx.Assertion {
+	return func(response *http.Response) error {
+		if server := response.Header.Get("Server"); version.MatchString(server) {
+			return fmt.Errorf("security: Server (%s) discloses version", server)
+		}
+		for _, name := range disclosing {
+			if value := response.Header.Get(name); value != "" {
+				return fmt.Errorf("security: %s (%s) discloses server details", name, value)
+			}
+		}
+		return nil
+	}
+}
+
+// CSP is a parsed Content-Security-Policy, mapping (lower-cased) directive names to their values.
+type CSP map[string][]string
+
+// ParseCSP parses the given Content-Security-Policy header values. A single value can contain multiple policies
+// separated by commas. As browsers do, directive names are case-insensitive and repeated directives are ignored.
+func ParseCSP(values ...string) (policies []CSP) {
+	for _, value := range values {
+		for _, serialized := range strings.Split(value, ",") {
+			var policy = make(CSP)
+			for _, directive := range strings.Split(serialized, ";") {
+				var fields = strings.Fields(directive)
+				if len(fields) == 0 {
+					continue
+				}
+				if name := strings.ToLower(fields[0]); policy[name] == nil {
+					policy[name] = append([]string{}, fields[1:]...)
+				}
+			}
+			if len(policy) > 0 {
+				policies = append(policies, policy)
+			}
+		}
+	}
+	return policies
+}
+
+// fallbacks lists the directives a fetch directive falls back to, in order, when it is not set
+var fallbacks = map[string][]string{
+	"script-src-elem": {"script-src", "default-src"},
+	"script-src-attr": {"script-src", "default-src"},
+	"style-src-elem":  {"style-src", "default-src"},
+	"style-src-attr":  {"style-src", "default-src"},
+	"frame-src":       {"child-src", "default-src"},
+	"worker-src":      {"child-src", "script-src", "default-src"},
+}
+
+// Sources returns the sources of the given directive, following the fallback rules for fetch directives
+// (for example, script-src falls back to default-src). The boolean is false if neither is set.
+func (c CSP) Sources(directive string) ([]string, bool) {
+	directive = strings.ToLower(directive)
+	if sources, ok := c[directive]; ok {
+		return sources, true
+	}
+
+	var chain, ok = fallbacks[directive]
+	if !ok && strings.HasSuffix(directive, "-src") {
+		chain = []string{"default-src"}
+	}
+	for _, d := range chain {
+		if sources, ok := c[d]; ok {
+			return sources, true
+		}
+	}
+	return nil, false
+}
+
+// CSPCheck defines a function that checks the parsed Content-Security-Policies set on a response.
+//
+// Browsers enforce every policy, and so a restriction (like CSPNoUnsafeInline()) holds if any of the policies
+// enforces it, whereas an allowance (like CSPDirective("img-src", matchers.Contains("'self'"))) holds only if every
+// policy allows it. The bundled checks follow these rules; use AnyCSP(...) and EveryCSP(...) to write your own.
+type CSPCheck func([]CSP) error
+
+// AnyCSP returns a CSPCheck that passes if any of the policies passes the given check. Use it for restrictions.
+func AnyCSP(check func(CSP) error) CSPCheck {
+	return func(policies []CSP) (err error) {
+		for _, policy := range policies {
+			if err = check(policy); err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// EveryCSP returns a CSPCheck that passes only if all the policies pass the given check. Use it for allowances.
+func EveryCSP(check func(CSP) error) CSPCheck {
+	return func(policies []CSP) error {
+		for _, policy := range policies {
+			if err := check(policy); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ToHaveCSP returns an assertion that checks whether a Content-Security-Policy is set and passes all the given checks.
+func ToHaveCSP(checks ...CSPCheck) httpx.Assertion {
+	return func(response *http.Response) error {
+		var policies = ParseCSP(response.Header.Values("Content-Security-Policy")...)
+		if len(policies) == 0 {
+			return fmt.Errorf("security: Content-Security-Policy not set")
+		}
+
+		for _, check := range checks {
+			if err := check(policies); err != nil {
+				return fmt.Errorf("security: Content-Security-Policy: %v", err)
+			}
+		}
+		return nil
+	}
+}
+
+// CSPDirective returns a CSPCheck that checks the sources (as a []string) of the given directive using the given matcher.
+// Fetch directives fall back to other directives (see CSP.Sources(...)) as browsers do.
+//
+// The directive must be set by at least one policy, and the sources of every policy that sets it must match,
+// as a policy that doesn't set the directive doesn't restrict it.
+func CSPDirective(directive string, m matchers.Matcher) CSPCheck {
+	return func(policies []CSP) error {
+		var set bool
+		for _, policy := range policies {
+			var sources, ok = policy.Sources(directive)
+			if !ok {
+				continue
+			}
+			set = true
+			if err := m(sources); err != nil {
+				return fmt.Errorf("%s: %v", directive, err)
+			}
+		}
+		return AssertThat(set, "%s not set", directive)
+	}
+}
+
+// CSPNoUnsafeInline returns a CSPCheck that checks that inline scripts are not allowed, ie. script-src (or default-src)
+// is set and does not allow 'unsafe-inline' in at least one policy. As browsers do, 'unsafe-inline' is ignored
+// if a nonce or hash is present.
+func CSPNoUnsafeInline() CSPCheck {
+	return AnyCSP(func(policy CSP) error {
+		var sources, ok = policy.Sources("script-src")
+		if !ok {
+			return fmt.Errorf("script-src not set, allowing inline scripts")
+		}
+
+		var unsafe bool
+		for _, source := range sources {
+			switch s := strings.ToLower(source); {
+			case strings.HasPrefix(s, "'nonce-"), strings.HasPrefix(s, "'sha256-"),
+				strings.HasPrefix(s, "'sha384-"), strings.HasPrefix(s, "'sha512-"):
+				return nil
+			case s == "'unsafe-inline'":
+				unsafe = true
+			}
+		}
+		return AssertThat(!unsafe, "script-src allows 'unsafe-inline'")
+	})
+}
+
+// CSPNoUnsafeEval returns a CSPCheck that checks that script-src (or default-src) is set
+// and does not allow 'unsafe-eval' in at least one policy.
+func CSPNoUnsafeEval() CSPCheck {
+	return AnyCSP(func(policy CSP) error {
+		var sources, ok = policy.Sources("script-src")
+		if !ok {
+			return fmt.Errorf("script-src not set, allowing eval()")
+		}
+		for _, source := range sources {
+			if strings.EqualFold(source, "'unsafe-eval'") {
+				return fmt.Errorf("script-src allows 'unsafe-eval'")
+			}
+		}
+		return nil
+	})
+}
+
+// splitDirective splits the given directive into its (trimmed) name and argument around the first separator
+func splitDirective(directive, sep string) (string, string) {
+	var parts = strings.SplitN(directive, sep, 2)
+	if len(parts) == 1 {
+		return strings.TrimSpace(parts[0]), ""
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,106 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secureHeaders returns a header that passes the strict profile, with the given overrides applied.
+// An override with an empty value removes the header.
+func secureHeaders(overrides map[string]string) http.Header {
+	var headers = map[string]string{
+		"Strict-Transport-Security": "max-age=63072000; includeSubDomains; preload",
+		"Content-Security-Policy":   "default-src 'self'; script-src 'self' 'nonce-abc' 'unsafe-inline'; object-src 'none'; frame-ancestors 'none'",
+		"X-Content-Type-Options":    "nosniff",
+		"Referrer-Policy":           "no-referrer, strict-origin-when-cross-origin",
+		"Permissions-Policy":        "camera=(), geolocation=(self)",
+		"Server":                    "nginx",
+	}
+	for name, value := range overrides {
+		headers[name] = value
+	}
+
+	var header = make(http.Header)
+	for name, value := range headers {
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+	return header
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	assert(t, SecurityHeaders(StrictSecurityProfile())(newResponse(http.StatusOK, secureHeaders(nil), "")) == nil, "must pass strict profile")
+	assert(t, SecurityHeaders(SecurityProfile{})(newResponse(http.StatusOK, nil, "")) == nil, "must pass empty profile")
+
+	var err = SecurityHeaders(BaselineSecurityProfile())(newResponse(http.StatusOK, secureHeaders(map[string]string{
+		"X-Content-Type-Options": "", "Server": "nginx/1.19.0",
+	}), ""))
+	assert(t, err != nil, "must fail baseline profile")
+	assert(t, strings.Contains(err.Error(), "2 of 5 failed"), "must report all failures: %v", err)
+}
+
+func TestToHaveHSTS(t *testing.T) {
+	var resp = newResponse(http.StatusOK, secureHeaders(map[string]string{"Strict-Transport-Security": "max-age=\"86400\""}), "")
+	assert(t, ToHaveHSTS(24*time.Hour, false)(resp) == nil, "must accept quoted max-age")
+	assert(t, ToHaveHSTS(48*time.Hour, false)(resp) != nil, "must fail if max-age is too low")
+	assert(t, ToHaveHSTS(time.Hour, true)(resp) != nil, "must fail without includeSubDomains")
+	assert(t, ToHaveHSTS(0, false)(newResponse(http.StatusOK, secureHeaders(map[string]string{"Strict-Transport-Security": "preload"}), "")) != nil, "must fail without max-age")
+	assert(t, ToHaveHSTS(0, false)(newResponse(http.StatusOK, secureHeaders(map[string]string{"Strict-Transport-Security": ""}), "")) != nil, "must fail if not set")
+}
+
+func TestToHaveCSP(t *testing.T) {
+	var resp = newResponse(http.StatusOK, secureHeaders(nil), "")
+	assert(t, ToHaveCSP(CSPNoUnsafeInline(), CSPNoUnsafeEval())(resp) == nil, "must ignore unsafe-inline with nonce")
+	assert(t, ToHaveCSP(CSPDirective("img-src", matchers.Contains("'self'")))(resp) == nil, "must fall back to default-src")
+	assert(t, ToHaveCSP(CSPDirective("form-action", matchers.Contains("'self'")))(resp) != nil, "must not fall back for non-fetch directives")
+
+	var unsafe = newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "Script-Src 'self' 'unsafe-inline' 'unsafe-eval'"}), "")
+	assert(t, ToHaveCSP(CSPNoUnsafeInline())(unsafe) != nil, "must fail with unsafe-inline")
+	assert(t, ToHaveCSP(CSPNoUnsafeEval())(unsafe) != nil, "must fail with unsafe-eval")
+	assert(t, ToHaveCSP(CSPNoUnsafeEval())(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "img-src *"}), "")) != nil, "must fail without script-src")
+
+	var multiple = newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "script-src 'unsafe-eval', default-src 'self'"}), "")
+	assert(t, ToHaveCSP(CSPNoUnsafeEval())(multiple) == nil, "must pass restriction if any policy enforces it")
+	assert(t, ToHaveCSP(CSPDirective("script-src", matchers.Contains("'unsafe-eval'")))(multiple) != nil, "must fail allowance if any policy disallows it")
+
+	var allowing = newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "img-src 'self' data:, script-src 'self', img-src 'self'"}), "")
+	assert(t, ToHaveCSP(CSPDirective("img-src", matchers.Contains("'self'")))(allowing) == nil, "must pass allowance if every policy setting it allows it")
+	assert(t, ToHaveCSP(CSPDirective("img-src", matchers.Contains("data:")))(allowing) != nil, "must fail allowance if any policy disallows it")
+	assert(t, len(ParseCSP("a b; c, d; ;a x")) == 2, "must parse multiple policies")
+	assert(t, ToHaveCSP()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": ""}), "")) != nil, "must fail if not set")
+}
+
+func TestToDenyFraming(t *testing.T) {
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(nil), "")) == nil, "must accept frame-ancestors")
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "frame-ancestors *"}), "")) != nil, "must reject wildcard frame-ancestors")
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "", "X-Frame-Options": "sameorigin"}), "")) == nil, "must accept X-Frame-Options")
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "", "X-Frame-Options": "ALLOW-FROM x"}), "")) != nil, "must reject other X-Frame-Options")
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": ""}), "")) != nil, "must fail if neither is set")
+
+	for _, policy := range []string{"frame-ancestors https:", "frame-ancestors 'self' http:", "frame-ancestors https://*", "frame-ancestors *:443"} {
+		assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": policy}), "")) != nil, "must reject %s", policy)
+	}
+	var policies = map[string]string{"Content-Security-Policy": "frame-ancestors *, frame-ancestors 'self' https://partner.example.com"}
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(policies), "")) == nil, "must accept if any policy restricts framing")
+	assert(t, ToDenyFraming()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Content-Security-Policy": "frame-ancestors https:, default-src 'self'", "X-Frame-Options": "DENY"}), "")) != nil, "frame-ancestors must supersede X-Frame-Options")
+}
+
+func TestOtherSecurityHeaders(t *testing.T) {
+	var resp = newResponse(http.StatusOK, secureHeaders(nil), "")
+	assert(t, ToHaveNoSniff()(resp) == nil, "must pass with nosniff")
+	assert(t, ToHaveReferrerPolicy("strict-origin-when-cross-origin")(resp) == nil, "must use the last policy")
+	assert(t, ToHaveReferrerPolicy("no-referrer")(resp) != nil, "must not use earlier policies")
+	assert(t, ToHaveReferrerPolicy(SafeReferrerPolicies()...)(newResponse(http.StatusOK, secureHeaders(map[string]string{"Referrer-Policy": "unsafe-url"}), "")) != nil, "must reject unsafe policy")
+	assert(t, ToHavePermissionsPolicy("camera", "Geolocation")(resp) == nil, "must find declared features")
+	assert(t, ToHavePermissionsPolicy("microphone")(resp) != nil, "must fail on undeclared features")
+	assert(t, ToHavePermissionsPolicy()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Permissions-Policy": "camera"}), "")) != nil, "must fail on malformed policy")
+
+	assert(t, NotDiscloseServerDetails()(resp) == nil, "must allow server without version")
+	assert(t, NotDiscloseServerDetails()(newResponse(http.StatusOK, secureHeaders(map[string]string{"Server": "Apache/2.4.1 (Unix)"}), "")) != nil, "must detect server version")
+	assert(t, NotDiscloseServerDetails()(newResponse(http.StatusOK, secureHeaders(map[string]string{"X-Powered-By": "Express"}), "")) != nil, "must detect X-Powered-By")
+}
@@ -0,0 +1,143 @@
+package assertions
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetLength is the maximum number of bytes of response body included in status failure messages
+const snippetLength = 256
+
+// ToBeSuccessful returns an assertion that checks whether the response status is 2xx
+func ToBeSuccessful() httpx.Assertion {
+	return statusClass(2, "successful (2xx)")
+}
+
+// ToBeRedirect returns an assertion that checks whether the response status is 3xx
+func ToBeRedirect() httpx.Assertion {
+	return statusClass(3, "redirect (3xx)")
+}
+
+// ToBeClientError returns an assertion that checks whether the response status is 4xx
+func ToBeClientError() httpx.Assertion {
+	return statusClass(4, "client error (4xx)")
+}
+
+// ToBeServerError returns an assertion that checks whether the response status is 5xx
+func ToBeServerError() httpx.Assertion {
+	return statusClass(5, "server error (5xx)")
+}
+
+// ToHaveStatusIn returns an assertion that checks whether the response status is one of the given statuses
+func ToHaveStatusIn(statuses ...int) httpx.Assertion {
+	var expected = make([]string, len(statuses))
+	for i, s := range statuses {
+		expected[i] = statusText(s)
+	}
+	return func(response *http.Response) error {
+		for _, s := range statuses {
+			if response.StatusCode == s {
+				return nil
+			}
+		}
+		return statusError(response, "one of "+strings.Join(expected, ", "))
+	}
+}
+
+// ToRedirectTo returns an assertion that checks whether the response is a redirect (3xx) to the given location.
+// Both the Location header and the expected location are resolved relative to the request url before being compared,
+// and so relative locations (like "/login") can be used.
+func ToRedirectTo(location string) httpx.Assertion {
+	return func(response *http.Response) error {
+		if response.StatusCode < 300 || response.StatusCode > 399 {
+			return statusError(response, "redirect (3xx)")
+		}
+
+		var header = response.Header.Get("Location")
+		if header == "" {
+			return fmt.Errorf("redirect: location header not set")
+		}
+
+		var actual, err = resolve(response, header)
+		if err != nil {
+			return fmt.Errorf("redirect: invalid location header '%s': %v", header, err)
+		}
+		var expected *url.URL
+		if expected, err = resolve(response, location); err != nil {
+			return fmt.Errorf("redirect: invalid expected location '%s': %v", location, err)
+		}
+
+		return AssertThat(actual.String() == expected.String(),
+			"redirect: location (%s) not equal to expected location (%s)", actual, expected)
+	}
+}
+
+// resolve parses the given location and resolves it relative to the request url (if available)
+func resolve(response *http.Response, location string) (*url.URL, error) {
+	var u, err = url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if response.Request != nil && response.Request.URL != nil {
+		u = response.Request.URL.ResolveReference(u)
+	}
+	return u, nil
+}
+
+// statusClass returns an assertion that checks whether the response status is of the given class (ie. Nxx)
+func statusClass(class int, description string) httpx.Assertion {
+	return func(response *http.Response) error {
+		if response.StatusCode/100 == class {
+			return nil
+		}
+		return statusError(response, description)
+	}
+}
+
+// statusError returns an error explaining that the response status isn't the expected one. It includes a snippet
+// of the response body in the message, as it usually explains why (say) a 500 happened.
+func statusError(response *http.Response, expected string) error {
+	var msg = fmt.Sprintf("status: returned status (%s) not equal to expected status (%s)", statusText(response.StatusCode), expected)
+	if snippet := bodySnippet(response); snippet != "" {
+		msg += "; body: " + snippet
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// statusText returns the status code along with its text, eg. "404 Not Found"
+func statusText(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return fmt.Sprintf("%d %s", status, text)
+	}
+	return fmt.Sprintf("%d", status)
+}
+
+// bodySnippet returns (at most snippetLength bytes of) the response body, leaving the body readable
+func bodySnippet(response *http.Response) string {
+	if response.Body == nil {
+		return ""
+	}
+	var rewind, err = BufferBody(response)
+	if err != nil {
+		return ""
+	}
+	defer rewind()
+
+	var buf = make([]byte, snippetLength+1)
+	var n, _ = io.ReadFull(response.Body, buf)
+	if n <= snippetLength {
+		return strings.TrimSpace(string(buf[:n]))
+	}
+
+	var snippet = buf[:snippetLength]
+	for len(snippet) > 0 && !utf8.Valid(snippet) {
+		snippet = snippet[:len(snippet)-1] // don't cut a multi-byte character in half
+	}
+	return strings.TrimSpace(string(snippet)) + "..."
+}
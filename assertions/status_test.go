@@ -0,0 +1,55 @@
+package assertions_test
+
+import (
+	. "go.riyazali.net/httpx/assertions"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusClasses(t *testing.T) {
+	var ok, redirect = newResponse(http.StatusNoContent, nil, ""), newResponse(http.StatusFound, nil, "")
+	var client, server = newResponse(http.StatusNotFound, nil, ""), newResponse(http.StatusBadGateway, nil, "")
+
+	assert(t, ToBeSuccessful()(ok) == nil && ToBeSuccessful()(redirect) != nil, "must check for 2xx")
+	assert(t, ToBeRedirect()(redirect) == nil && ToBeRedirect()(client) != nil, "must check for 3xx")
+	assert(t, ToBeClientError()(client) == nil && ToBeClientError()(server) != nil, "must check for 4xx")
+	assert(t, ToBeServerError()(server) == nil && ToBeServerError()(ok) != nil, "must check for 5xx")
+}
+
+func TestToHaveStatusIn(t *testing.T) {
+	var resp = newResponse(http.StatusCreated, nil, "")
+	assert(t, ToHaveStatusIn(http.StatusOK, http.StatusCreated)(resp) == nil, "must pass if status is one of given")
+	assert(t, ToHaveStatusIn(http.StatusOK, http.StatusAccepted)(resp) != nil, "must fail if status is not one of given")
+}
+
+func TestStatusFailureMessage(t *testing.T) {
+	var resp = newResponse(http.StatusInternalServerError, nil, "  database is down\n")
+	var err = ToBeSuccessful()(resp)
+	assert(t, err != nil, "must fail")
+	assert(t, strings.Contains(err.Error(), "500 Internal Server Error"), "must include status text: %v", err)
+	assert(t, strings.HasSuffix(err.Error(), "body: database is down"), "must include body snippet: %v", err)
+
+	err = ToHaveStatus(http.StatusOK)(newResponse(http.StatusBadRequest, nil, strings.Repeat("x", 1024)))
+	assert(t, strings.HasSuffix(err.Error(), strings.Repeat("x", 256)+"..."), "must truncate long bodies: %v", err)
+}
+
+func TestToRedirectTo(t *testing.T) {
+	var newRedirect = func(status int, location string) *http.Response {
+		var header = make(http.Header)
+		if location != "" {
+			header.Set("Location", location)
+		}
+		var resp = newResponse(status, header, "")
+		resp.Request = httptest.NewRequest(http.MethodGet, "https://example.com/a/b?c=d", nil)
+		return resp
+	}
+
+	assert(t, ToRedirectTo("/login")(newRedirect(http.StatusFound, "/login")) == nil, "must match same location")
+	assert(t, ToRedirectTo("https://example.com/a/login")(newRedirect(http.StatusSeeOther, "login")) == nil, "must resolve relative location")
+	assert(t, ToRedirectTo("/login")(newRedirect(http.StatusFound, "https://example.com/login")) == nil, "must resolve expected location")
+	assert(t, ToRedirectTo("/login")(newRedirect(http.StatusFound, "/logout")) != nil, "must fail if location differs")
+	assert(t, ToRedirectTo("/login")(newRedirect(http.StatusOK, "/login")) != nil, "must fail if not a redirect")
+	assert(t, ToRedirectTo("/login")(newRedirect(http.StatusFound, "")) != nil, "must fail if location is not set")
+}
@@ -0,0 +1,388 @@
+package assertions
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// streamWriter adapts a pair of functions into the io.WriteCloser expected by httpx.StreamAssertion
+type streamWriter struct {
+	write func([]byte) error
+	close func() error
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if err := s.write(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *streamWriter) Close() error { return s.close() }
+
+// failedStream returns a noop stream assertion that always returns an error
+func failedStream(err error) io.WriteCloser {
+	return &streamWriter{
+		write: func([]byte) error { return err },
+		close: func() error { return err },
+	}
+}
+
+// TotalSize returns a stream assertion that counts the bytes in the response body and checks the total
+// (as an int64) using the given matcher.
+//
+//  StreamRequest(Get("/download")).ExpectIt(t, TotalSize(matchers.Equal(4<<30)))
+func TotalSize(m matchers.Matcher) httpx.StreamAssertion {
+	return func(*http.Response) io.WriteCloser {
+		var size int64
+		return &streamWriter{
+			write: func(p []byte) error { size += int64(len(p)); return nil },
+			close: func() error {
+				if err := m(size); err != nil {
+					return fmt.Errorf("stream: size: %v", err)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// MaxSize returns a stream assertion that fails as soon as the response body grows larger than n bytes.
+// Unlike TotalSize(...), it doesn't need to see the end of the body to fail, and so can be used with infinite streams.
+func MaxSize(n int64) httpx.StreamAssertion {
+	return func(*http.Response) io.WriteCloser {
+		var size int64
+		return &streamWriter{
+			write: func(p []byte) error {
+				if size += int64(len(p)); size > n {
+					return fmt.Errorf("stream: body is larger than %d bytes", n)
+				}
+				return nil
+			},
+			close: func() error { return nil },
+		}
+	}
+}
+
+// SizeMatchesContentLength returns a stream assertion that checks whether the size of the response body
+// matches its Content-Length header. It fails if the response has no Content-Length (say, a chunked response).
+func SizeMatchesContentLength() httpx.StreamAssertion {
+	return func(response *http.Response) io.WriteCloser {
+		var length = response.ContentLength
+		if length < 0 {
+			return failedStream(fmt.Errorf("stream: response has no Content-Length"))
+		}
+
+		var size int64
+		return &streamWriter{
+			write: func(p []byte) error {
+				if size += int64(len(p)); size > length {
+					return fmt.Errorf("stream: body is larger than Content-Length %d", length)
+				}
+				return nil
+			},
+			close: func() error {
+				return AssertThat(size == length, "stream: body has %d bytes but Content-Length is %d", size, length)
+			},
+		}
+	}
+}
+
+// Checksum returns a stream assertion that hashes the response body using the given hash function
+// and compares it against the expected (hex-encoded, case-insensitive) checksum.
+//
+//  Checksum(sha1.New, "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed")
+func Checksum(h func() hash.Hash, expected string) httpx.StreamAssertion {
+	return func(*http.Response) io.WriteCloser {
+		var want, err = hex.DecodeString(strings.TrimSpace(expected))
+		if err != nil {
+			return failedStream(fmt.Errorf("stream: invalid checksum %q: %v", expected, err))
+		}
+
+		var digest = h()
+		return &streamWriter{
+			write: func(p []byte) error { _, _ = digest.Write(p); return nil },
+			close: func() error {
+				var got = digest.Sum(nil)
+				return AssertThat(bytes.Equal(got, want), "stream: expected checksum %x, got %x", want, got)
+			},
+		}
+	}
+}
+
+// SHA256 returns a stream assertion that checks the SHA-256 checksum of the response body. See Checksum(...)
+func SHA256(expected string) httpx.StreamAssertion { return Checksum(sha256.New, expected) }
+
+// MD5 returns a stream assertion that checks the MD5 checksum of the response body. See Checksum(...)
+func MD5(expected string) httpx.StreamAssertion { return Checksum(md5.New, expected) }
+
+// digestAlgorithms maps (lower-cased) digest algorithm names, as used in Digest and Content-Digest headers,
+// to their hash functions
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+	"md5":     md5.New,
+}
+
+// expectedDigest is a digest advertised by one of the response headers
+type expectedDigest struct {
+	header, algorithm string
+	value             []byte
+}
+
+// DigestMatchesHeader returns a stream assertion that checks the response body against the digests advertised
+// in its headers. It understands Content-Digest and Repr-Digest (RFC 9530), Digest (RFC 3230) and Content-MD5,
+// with the sha-256, sha-512 and md5 algorithms, and checks every supported digest present.
+// It fails if the response advertises no supported digest.
+//
+// Repr-Digest and Digest cover the complete representation, and so are ignored for partial (206) responses.
+// Digests cannot be verified if the client transparently decompressed the body (see http.Response.Uncompressed).
+func DigestMatchesHeader() httpx.StreamAssertion {
+	return func(response *http.Response) io.WriteCloser {
+		if response.Uncompressed {
+			return failedStream(fmt.Errorf("stream: body was transparently decompressed; cannot verify digest"))
+		}
+
+		var expected, err = digests(response)
+		if err != nil {
+			return failedStream(fmt.Errorf("stream: %v", err))
+		} else if len(expected) == 0 {
+			return failedStream(fmt.Errorf("stream: response has no supported digest header"))
+		}
+
+		var hashes = make(map[string]hash.Hash)
+		for _, e := range expected {
+			if _, ok := hashes[e.algorithm]; !ok {
+				hashes[e.algorithm] = digestAlgorithms[e.algorithm]()
+			}
+		}
+
+		return &streamWriter{
+			write: func(p []byte) error {
+				for _, h := range hashes {
+					_, _ = h.Write(p)
+				}
+				return nil
+			},
+			close: func() error {
+				var errs []error
+				for _, e := range expected {
+					var got = hashes[e.algorithm].Sum(nil)
+					if !bytes.Equal(got, e.value) {
+						errs = append(errs, fmt.Errorf("%s: expected %s digest %s, got %s", e.header, e.algorithm,
+							base64.StdEncoding.EncodeToString(e.value), base64.StdEncoding.EncodeToString(got)))
+					}
+				}
+				if len(errs) > 0 {
+					return fmt.Errorf("stream: digest mismatch:%s", list(errs))
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// digests returns all the supported digests advertised by the response headers
+func digests(response *http.Response) (expected []expectedDigest, err error) {
+	var partial = response.StatusCode == http.StatusPartialContent
+
+	var add = func(header, algorithm, value string) error {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		if _, ok := digestAlgorithms[algorithm]; !ok {
+			return nil // unsupported algorithms are ignored
+		}
+		var decoded, err = base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: invalid %s digest %q", header, algorithm, value)
+		}
+		expected = append(expected, expectedDigest{header: header, algorithm: algorithm, value: decoded})
+		return nil
+	}
+
+	var headers = []string{"Content-Digest", "Repr-Digest", "Digest"}
+	for _, header := range headers {
+		if partial && header != "Content-Digest" {
+			continue
+		}
+		for _, member := range ParseList(response.Header.Values(header)...) {
+			var algorithm, value = splitDirective(member, "=")
+			if header != "Digest" {
+				// structured field byte sequences are wrapped in colons, and may carry parameters
+				if i := strings.Index(value, ";"); i >= 0 {
+					value = value[:i]
+				}
+				value = strings.Trim(strings.TrimSpace(value), ":")
+			}
+			if err = add(header, algorithm, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if value := response.Header.Get("Content-MD5"); value != "" {
+		if err = add("Content-MD5", "md5", value); err != nil {
+			return nil, err
+		}
+	}
+	return expected, nil
+}
+
+// ByteRange returns a stream assertion that checks whether the response body contains the expected bytes
+// at the given offset. It is satisfied (see httpx.ErrSatisfied) as soon as the range has been read,
+// and so can be used with infinite streams.
+//
+//  ByteRange(0, []byte("\x89PNG\r\n\x1a\n")) // check the file signature
+func ByteRange(offset int64, expected []byte) httpx.StreamAssertion {
+	return func(*http.Response) io.WriteCloser {
+		var end = offset + int64(len(expected))
+		var pos int64
+		return &streamWriter{
+			write: func(p []byte) error {
+				var start = pos
+				pos += int64(len(p))
+				var from, to = offset, end
+				if start > from {
+					from = start
+				}
+				if pos < to {
+					to = pos
+				}
+				for i := from; i < to; i++ {
+					if got, want := p[i-start], expected[i-offset]; got != want {
+						return fmt.Errorf("stream: byte at offset %d: expected 0x%02x, got 0x%02x", i, want, got)
+					}
+				}
+				if pos >= end {
+					return httpx.ErrSatisfied
+				}
+				return nil
+			},
+			close: func() error {
+				return AssertThat(pos >= end, "stream: body ended after %d bytes, before range [%d, %d)", pos, offset, end)
+			},
+		}
+	}
+}
+
+var contentRange = regexp.MustCompile(`^bytes (\d+)-(\d+)/(\d+|\*)$`)
+
+// ToMatchContentRange returns a stream assertion that checks whether a partial (206) response has a valid
+// Content-Range header, and whether the size of its body matches the range.
+//
+//  StreamRequest(Get("/download"), WithHeader("Range", "bytes=0-1023")).ExpectIt(t, ToMatchContentRange())
+func ToMatchContentRange() httpx.StreamAssertion {
+	return func(response *http.Response) io.WriteCloser {
+		if response.StatusCode != http.StatusPartialContent {
+			return failedStream(fmt.Errorf("stream: expected status %d, got %d", http.StatusPartialContent, response.StatusCode))
+		}
+
+		var header = response.Header.Get("Content-Range")
+		var match = contentRange.FindStringSubmatch(header)
+		if match == nil {
+			return failedStream(fmt.Errorf("stream: invalid Content-Range %q", header))
+		}
+		var first, _ = strconv.ParseInt(match[1], 10, 64)
+		var last, _ = strconv.ParseInt(match[2], 10, 64)
+		if first > last {
+			return failedStream(fmt.Errorf("stream: invalid Content-Range %q: first byte is after last byte", header))
+		}
+		if match[3] != "*" {
+			if complete, _ := strconv.ParseInt(match[3], 10, 64); last >= complete {
+				return failedStream(fmt.Errorf("stream: invalid Content-Range %q: range exceeds complete length", header))
+			}
+		}
+
+		var length = last - first + 1
+		var size int64
+		return &streamWriter{
+			write: func(p []byte) error {
+				if size += int64(len(p)); size > length {
+					return fmt.Errorf("stream: body is larger than Content-Range %q", header)
+				}
+				return nil
+			},
+			close: func() error {
+				return AssertThat(size == length, "stream: body has %d bytes but Content-Range %q has %d", size, header, length)
+			},
+		}
+	}
+}
+
+// Spool returns a stream assertion that runs the given (regular) assertions on the response body, without
+// holding all of it in memory. The body is buffered in memory up to threshold bytes, beyond which it is spooled
+// to a temporary file instead. Once the body has been read, each assertion is run with a fresh reader over
+// the spooled body (just like with MakeRequest(...)), and the temporary file is then removed.
+//
+//  StreamRequest(Get("/export.json")).ExpectIt(t,
+//    SHA256("..."),
+//    Spool(1<<20, ToHaveStatus(http.StatusOK), JsonPath("items[0].id", matchers.Equal(1))),
+//  )
+//
+// Note that assertions that read the whole body into memory (like BodyJson(...)) still do so.
+func Spool(threshold int64, assertions ...httpx.Assertion) httpx.StreamAssertion {
+	return func(response *http.Response) io.WriteCloser {
+		var buf bytes.Buffer
+		var file *os.File
+		var size int64
+		return &streamWriter{
+			write: func(p []byte) (err error) {
+				if file == nil && int64(buf.Len()+len(p)) > threshold {
+					if file, err = ioutil.TempFile("", "httpx-spool-*"); err != nil {
+						return fmt.Errorf("spool: failed to create temporary file: %v", err)
+					}
+					if _, err = buf.WriteTo(file); err != nil {
+						return fmt.Errorf("spool: failed to write temporary file: %v", err)
+					}
+				}
+				if file == nil {
+					_, _ = buf.Write(p)
+				} else if _, err = file.Write(p); err != nil {
+					return fmt.Errorf("spool: failed to write temporary file: %v", err)
+				}
+				size += int64(len(p))
+				return nil
+			},
+			close: func() error {
+				var reader io.ReaderAt = bytes.NewReader(buf.Bytes())
+				if file != nil {
+					defer func() { _ = file.Close(); _ = os.Remove(file.Name()) }()
+					reader = file
+				}
+
+				var errs = make([]error, len(assertions))
+				var failed = 0
+				for i, fn := range assertions {
+					// work on a shallow copy so that the original response isn't tampered with
+					var r = *response
+					r.Body = ioutil.NopCloser(io.NewSectionReader(reader, 0, size))
+					if errs[i] = fn(&r); errs[i] != nil {
+						failed++
+					}
+					if httpx.IsFatal(errs[i]) {
+						break
+					}
+				}
+				if failed > 0 {
+					return fmt.Errorf("spool: %d of %d failed:%s", failed, len(assertions), list(errs))
+				}
+				return nil
+			},
+		}
+	}
+}
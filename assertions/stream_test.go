@@ -0,0 +1,150 @@
+package assertions_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// streamed streams the given body through the assertion and returns its outcome
+func streamed(assertion StreamAssertion, status int, headers map[string]string, body string) error {
+	var fn = ExecFn(func(*http.Request) (*http.Response, error) {
+		var response = &http.Response{StatusCode: status, Header: make(http.Header), ContentLength: -1,
+			Body: ioutil.NopCloser(strings.NewReader(body))}
+		for name, value := range headers {
+			response.Header.Set(name, value)
+		}
+		if cl, ok := headers["Content-Length"]; ok {
+			_, _ = fmt.Sscan(cl, &response.ContentLength)
+		}
+		return response, nil
+	})
+
+	var result = fn.StreamRequest(Get("https://example.com")).Evaluate(assertion)
+	if result.Err != nil {
+		return result.Err
+	}
+	return result.Outcomes[0].Err
+}
+
+func TestStreamSize(t *testing.T) {
+	assert(t, streamed(TotalSize(matchers.Equal(5)), 200, nil, "hello") == nil, "must pass for matching size")
+	assert(t, streamed(TotalSize(matchers.Equal(4)), 200, nil, "hello") != nil, "must fail for mismatched size")
+	assert(t, streamed(MaxSize(5), 200, nil, "hello") == nil, "must pass within limit")
+	assert(t, streamed(MaxSize(4), 200, nil, "hello") != nil, "must fail beyond limit")
+
+	var withLength = func(n string) map[string]string { return map[string]string{"Content-Length": n} }
+	assert(t, streamed(SizeMatchesContentLength(), 200, withLength("5"), "hello") == nil, "must pass for matching Content-Length")
+	assert(t, streamed(SizeMatchesContentLength(), 200, withLength("6"), "hello") != nil, "must fail for short body")
+	assert(t, streamed(SizeMatchesContentLength(), 200, withLength("4"), "hello") != nil, "must fail for long body")
+	assert(t, streamed(SizeMatchesContentLength(), 200, nil, "hello") != nil, "must fail without Content-Length")
+}
+
+func TestChecksum(t *testing.T) {
+	var sha = sha256.Sum256([]byte("hello"))
+	var md = md5.Sum([]byte("hello"))
+
+	assert(t, streamed(SHA256(hex.EncodeToString(sha[:])), 200, nil, "hello") == nil, "must pass for matching sha-256")
+	assert(t, streamed(SHA256(strings.ToUpper(hex.EncodeToString(sha[:]))), 200, nil, "hello") == nil, "must ignore case")
+	assert(t, streamed(SHA256(hex.EncodeToString(sha[:])), 200, nil, "hellO") != nil, "must fail for mismatched sha-256")
+	assert(t, streamed(MD5(hex.EncodeToString(md[:])), 200, nil, "hello") == nil, "must pass for matching md5")
+	assert(t, streamed(MD5("not-hex"), 200, nil, "hello") != nil, "must fail for invalid checksum")
+}
+
+func TestDigestMatchesHeader(t *testing.T) {
+	var sha = sha256.Sum256([]byte("hello"))
+	var md = md5.Sum([]byte("hello"))
+	var sha64, md64 = base64.StdEncoding.EncodeToString(sha[:]), base64.StdEncoding.EncodeToString(md[:])
+
+	var cases = []struct {
+		name    string
+		status  int
+		headers map[string]string
+		pass    bool
+	}{
+		{"content-digest", 200, map[string]string{"Content-Digest": "sha-256=:" + sha64 + ":"}, true},
+		{"repr-digest checks every algorithm", 200, map[string]string{"Repr-Digest": "sha-512=:" + sha64 + ":, sha-256=:" + sha64 + ":"}, false},
+		{"digest", 200, map[string]string{"Digest": "SHA-256=" + sha64 + ",unixsum=30637"}, true},
+		{"content-md5", 200, map[string]string{"Content-MD5": md64}, true},
+		{"mismatch", 200, map[string]string{"Content-Digest": "sha-256=:" + md64 + ":"}, false},
+		{"invalid", 200, map[string]string{"Digest": "md5=???"}, false},
+		{"unsupported only", 200, map[string]string{"Digest": "unixsum=30637"}, false},
+		{"none", 200, nil, false},
+		{"partial ignores repr-digest", 206, map[string]string{"Repr-Digest": "sha-256=:" + md64 + ":", "Content-MD5": md64}, true},
+	}
+
+	for _, c := range cases {
+		var err = streamed(DigestMatchesHeader(), c.status, c.headers, "hello")
+		assert(t, (err == nil) == c.pass, "%s: unexpected outcome: %v", c.name, err)
+	}
+}
+
+func TestByteRange(t *testing.T) {
+	assert(t, streamed(ByteRange(0, []byte("he")), 200, nil, "hello") == nil, "must pass for matching prefix")
+	assert(t, streamed(ByteRange(3, []byte("lo")), 200, nil, "hello") == nil, "must pass for matching suffix")
+	assert(t, streamed(ByteRange(1, []byte("ex")), 200, nil, "hello") != nil, "must fail for mismatched bytes")
+	assert(t, streamed(ByteRange(4, []byte("o!")), 200, nil, "hello") != nil, "must fail if body ends before range")
+
+	// a range spanning multiple chunks
+	var body = strings.Repeat("a", 40*1024) + "bc"
+	assert(t, streamed(ByteRange(40*1024-1, []byte("abc")), 200, nil, body) == nil, "must match range across chunks")
+}
+
+func TestToMatchContentRange(t *testing.T) {
+	var withRange = func(r string) map[string]string { return map[string]string{"Content-Range": r} }
+
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 0-4/10"), "hello") == nil, "must pass for matching range")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 5-9/*"), "hello") == nil, "must allow unknown length")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 0-5/10"), "hello") != nil, "must fail for short body")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 0-3/10"), "hello") != nil, "must fail for long body")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 5-9/5"), "hello") != nil, "must fail if range exceeds length")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("bytes 4-0/10"), "hello") != nil, "must fail for inverted range")
+	assert(t, streamed(ToMatchContentRange(), 206, withRange("0-4/10"), "hello") != nil, "must fail for invalid header")
+	assert(t, streamed(ToMatchContentRange(), 200, withRange("bytes 0-4/10"), "hello") != nil, "must fail for non-partial response")
+}
+
+func TestSpool(t *testing.T) {
+	var body = func(expected string) func(*http.Response) error {
+		return BodyBytes(func(b []byte) error {
+			if !bytes.Equal(b, []byte(expected)) {
+				return fmt.Errorf("unexpected body %q", b)
+			}
+			return nil
+		})
+	}
+
+	t.Run("should run assertions on in-memory body", func(t *testing.T) {
+		assert(t, streamed(Spool(10, ToHaveStatus(200), body("hello"), body("hello")), 200, nil, "hello") == nil, "must pass")
+
+		var err = streamed(Spool(10, ToHaveStatus(201), body("hello"), body("world")), 200, nil, "hello")
+		assert(t, err != nil && strings.Contains(err.Error(), "2 of 3 failed"), "must report every failure: %v", err)
+	})
+
+	t.Run("should spool to temporary file past threshold", func(t *testing.T) {
+		var before, _ = filepath.Glob(filepath.Join(os.TempDir(), "httpx-spool-*"))
+		var large = strings.Repeat("hello", 20*1024)
+
+		assert(t, streamed(Spool(1024, body(large), body(large)), 200, nil, large) == nil, "must pass for spooled body")
+		assert(t, streamed(Spool(0, body("hello")), 200, nil, "hello") == nil, "must always spool with zero threshold")
+
+		var after, _ = filepath.Glob(filepath.Join(os.TempDir(), "httpx-spool-*"))
+		assert(t, len(after) == len(before), "must remove temporary files")
+	})
+
+	t.Run("should stop at fatal failure", func(t *testing.T) {
+		var err = streamed(Spool(10, Require(ToHaveStatus(201)), body("world")), 200, nil, "hello")
+		assert(t, err != nil && strings.Contains(err.Error(), "1 of 2 failed"), "must skip remaining assertions: %v", err)
+	})
+}
@@ -0,0 +1,107 @@
+package assertions
+
+import (
+	"encoding/xml"
+	"fmt"
+	"go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/matchers"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// BodyXml returns an assertion that un-marshal the response body (using encoding/xml) and invoke the given callback
+// with the decoded value. The given callback must be function with following signature,
+//    func cb(x X) error
+// where X can be any type that xml.Decoder supports.
+//
+// Use opts to perform checks before decoding the body, like EnforceXmlMediaType().
+func BodyXml(cb interface{}, opts ...BodyOption) httpx.Assertion {
+	return decodeBody("xml", cb, opts, func(body io.Reader, v interface{}) error {
+		return xml.NewDecoder(body).Decode(v)
+	})
+}
+
+// EnforceXmlMediaType returns a BodyOption that makes sure the response's media type is either application/xml,
+// text/xml or has a +xml structured syntax suffix (like application/atom+xml) before its body is decoded.
+func EnforceXmlMediaType() BodyOption {
+	return func(response *http.Response) error {
+		var err = ToHaveContentType("application/xml")(response)
+		if err != nil && (ToHaveContentType("text/xml")(response) == nil || ToHaveContentType("*/*+xml")(response) == nil) {
+			return nil
+		}
+		return err
+	}
+}
+
+// Namespaces maps the namespace prefixes used in XPath expressions to namespace uris.
+type Namespaces map[string]string
+
+// XPath returns an assertion that parses the response body as xml, evaluates the given XPath expression
+// and checks the result using the given matcher. See XPathNS(...) for details.
+//
+//  XPath("/rss/channel/item[1]/title", matchers.Equal("Hello World"))
+func XPath(expr string, m matchers.Matcher) httpx.Assertion {
+	return XPathNS(nil, expr, m)
+}
+
+// XPathNS is like XPath(...) but resolves the namespace prefixes used in the expression using the given namespaces.
+// As in XPath 1.0, unprefixed names only match elements without a namespace, and so documents with a default namespace
+// (like Atom feeds) need a prefix, like,
+//
+//  XPathNS(Namespaces{"atom": "http://www.w3.org/2005/Atom"}, "/atom:feed/atom:entry[1]/atom:title", matchers.Equal("x"))
+//
+// The expression is a location path using a subset of XPath 1.0: absolute and descendant (//) steps, name tests
+// (with * wildcards), attributes (@id), text(), . and .. steps and predicates by position ([1]),
+// attribute ([@id], [@id='x']), child element ([name], [name='x']) or text ([text()='x']).
+//
+// The matcher is given the (whitespace-trimmed) string value of the selected node if a single node is selected,
+// or a []string of string values if multiple nodes are selected. If the expression is wrapped in count(...),
+// the matcher is given the number of selected nodes instead. Unless count(...) is used, the assertion fails
+// if no node is selected.
+func XPathNS(namespaces Namespaces, expr string, m matchers.Matcher) httpx.Assertion {
+	var path, count = strings.TrimSpace(expr), false
+	if strings.HasPrefix(path, "count(") && strings.HasSuffix(path, ")") {
+		path, count = path[len("count("):len(path)-1], true
+	}
+
+	var compiled, err = compileXPath(path, namespaces)
+	if err != nil {
+		return failed(fmt.Errorf("xml: invalid xpath '%s': %v", expr, err))
+	}
+
+	return func(response *http.Response) (err error) {
+		defer checkClose(response.Body, &err)
+
+		var doc *xmlNode
+		if doc, err = parseXml(response.Body); err != nil {
+			return fmt.Errorf("xml: failed to decode response body: %v", err)
+		}
+
+		var nodes []*xmlNode
+		if nodes, err = compiled.eval(doc); err != nil {
+			return fmt.Errorf("xml: %s: %v", expr, err)
+		}
+
+		var value interface{}
+		switch {
+		case count:
+			value = len(nodes)
+		case len(nodes) == 0:
+			return fmt.Errorf("xml: %s: no node selected", expr)
+		case len(nodes) == 1:
+			value = strings.TrimSpace(nodes[0].text())
+		default:
+			var values = make([]string, len(nodes))
+			for i, n := range nodes {
+				values[i] = strings.TrimSpace(n.text())
+			}
+			value = values
+		}
+
+		if err = m(value); err != nil {
+			return fmt.Errorf("xml: %s: %v", expr, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package assertions_test
+
+import (
+	"encoding/xml"
+	"errors"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/matchers"
+	"net/http"
+	"testing"
+)
+
+const feed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/" xml:lang="en">
+  <title>Example Feed</title>
+  <link href="http://example.org/" rel="alternate"/>
+  <entry id="1">
+    <title>First</title>
+    <media:thumbnail url="http://example.org/1.png"/>
+  </entry>
+  <entry id="2">
+    <title><![CDATA[Second & last]]></title>
+  </entry>
+</feed>`
+
+const rss = `<rss version="2.0"><channel><title>News</title><item><title>Hello</title></item><item><title>World</title></item></channel></rss>`
+
+func TestBodyXml(t *testing.T) {
+	type item struct {
+		Title string `xml:"title"`
+	}
+	type channel struct {
+		Items []item `xml:"channel>item"`
+	}
+
+	var called bool
+	var err = BodyXml(func(c channel) error {
+		called = true
+		if len(c.Items) != 2 || c.Items[1].Title != "World" {
+			return errors.New("unexpected items")
+		}
+		return nil
+	}, EnforceXmlMediaType())(newResponse(http.StatusOK, contentType("application/rss+xml"), rss))
+	assert(t, err == nil && called, "must decode body and invoke callback: %v", err)
+
+	assert(t, BodyXml(func(c channel) error { return nil })(newResponse(http.StatusOK, contentType("text/xml"), "<rss>")) != nil, "must fail on malformed body")
+	assert(t, BodyXml(func(c channel) error { return nil }, EnforceXmlMediaType())(newResponse(http.StatusOK, contentType("application/json"), rss)) != nil, "must enforce media type")
+	assert(t, BodyXml(func(c channel) error { return errors.New("test") })(newResponse(http.StatusOK, contentType("text/xml"), rss)) != nil, "must return callback error")
+	assert(t, BodyXml(func(a, b xml.Name) error { return nil })(nil) != nil, "must check callback signature")
+}
+
+func TestXPath(t *testing.T) {
+	var rssResponse = func() *http.Response { return newResponse(http.StatusOK, contentType("application/xml"), rss) }
+	assert(t, XPath("/rss/channel/title", matchers.Equal("News"))(rssResponse()) == nil, "must select single element")
+	assert(t, XPath("//item/title", matchers.Equal([]string{"Hello", "World"}))(rssResponse()) == nil, "must select multiple elements")
+	assert(t, XPath("//item[2]/title/text()", matchers.Equal("World"))(rssResponse()) == nil, "must select by position")
+	assert(t, XPath("/rss/@version", matchers.Equal("2.0"))(rssResponse()) == nil, "must select attributes")
+	assert(t, XPath("//item[title='Hello']/../title", matchers.Equal("News"))(rssResponse()) == nil, "must filter by child and select parent")
+	assert(t, XPath("count(//item)", matchers.Equal(2))(rssResponse()) == nil, "must count nodes")
+	assert(t, XPath("count(//missing)", matchers.Equal(0))(rssResponse()) == nil, "must count no nodes")
+	assert(t, XPath("//missing", matchers.Nil())(rssResponse()) != nil, "must fail if nothing is selected")
+	assert(t, XPath("/rss/channel/title", matchers.Equal("x"))(rssResponse()) != nil, "must fail if matcher fails")
+	assert(t, XPath("/rss[", matchers.Nil())(rssResponse()) != nil, "must fail on invalid path")
+	assert(t, XPath("//item[title!='x']", matchers.Nil())(rssResponse()) != nil, "must fail on unsupported predicate")
+	assert(t, XPath("/rss", matchers.Nil())(newResponse(http.StatusOK, contentType("application/xml"), "not xml")) != nil, "must fail on invalid body")
+}
+
+func TestXPathNamespaces(t *testing.T) {
+	var ns = Namespaces{"a": "http://www.w3.org/2005/Atom", "m": "http://search.yahoo.com/mrss/"}
+	var feedResponse = func() *http.Response { return newResponse(http.StatusOK, contentType("application/atom+xml"), feed) }
+
+	assert(t, XPathNS(ns, "/a:feed/a:title", matchers.Equal("Example Feed"))(feedResponse()) == nil, "must resolve prefixes")
+	assert(t, XPathNS(ns, "/a:feed/a:entry[@id='2']/a:title", matchers.Equal("Second & last"))(feedResponse()) == nil, "must read cdata")
+	assert(t, XPathNS(ns, "//m:thumbnail/@url", matchers.HasSuffix("1.png"))(feedResponse()) == nil, "must match other namespaces")
+	assert(t, XPathNS(ns, "/a:feed/@xml:lang", matchers.Equal("en"))(feedResponse()) == nil, "must resolve xml prefix")
+	assert(t, XPathNS(ns, "count(/a:feed/@*)", matchers.Equal(1))(feedResponse()) == nil, "must not treat namespace declarations as attributes")
+	assert(t, XPathNS(ns, "count(//a:entry/*)", matchers.Equal(3))(feedResponse()) == nil, "must match wildcard in any namespace")
+	assert(t, XPath("count(/feed)", matchers.Equal(0))(feedResponse()) == nil, "unprefixed names must not match default namespace")
+	assert(t, XPath("/x:feed", matchers.Nil())(feedResponse()) != nil, "must fail on undeclared prefix")
+}
@@ -0,0 +1,311 @@
+package assertions
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// This file implements a small subset of XPath 1.0 over a minimal in-memory xml tree. Supported are,
+//
+//  - absolute (/a/b) and descendant (//b, /a//b) location paths, with . and .. steps
+//  - name tests (with namespace prefixes, like atom:entry), * wildcards, text() and attributes (@id, @*, @xml:lang)
+//  - predicates by position ([2]), attribute presence or value ([@id], [@id='1']),
+//    child element presence or text ([title], [title='x']) and text ([text()='x'])
+//  - count(path) as the outermost expression
+
+// node kinds
+const (
+	documentNode = iota
+	elementNode
+	attributeNode
+	textNode
+)
+
+// xmlNode is a node in the parsed xml tree. Element and attribute names have their namespace resolved to a uri.
+type xmlNode struct {
+	kind     int
+	name     xml.Name
+	value    string // for attribute and text nodes
+	parent   *xmlNode
+	children []*xmlNode
+	attrs    []*xmlNode
+}
+
+// parseXml parses the given xml document into a tree
+func parseXml(r io.Reader) (*xmlNode, error) {
+	var doc = &xmlNode{kind: documentNode}
+	var current = doc
+	var decoder = xml.NewDecoder(r)
+	for {
+		var token, err = decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var el = &xmlNode{kind: elementNode, name: t.Name, parent: current}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue // namespace declarations aren't attributes in the xpath data model
+				}
+				el.attrs = append(el.attrs, &xmlNode{kind: attributeNode, name: attr.Name, value: attr.Value, parent: el})
+			}
+			current.children = append(current.children, el)
+			current = el
+		case xml.EndElement:
+			current = current.parent
+		case xml.CharData:
+			if current != doc {
+				current.children = append(current.children, &xmlNode{kind: textNode, value: string(t), parent: current})
+			}
+		}
+	}
+	if len(doc.children) == 0 {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return doc, nil
+}
+
+// text returns the string value of the node
+func (n *xmlNode) text() string {
+	if n.kind == attributeNode || n.kind == textNode {
+		return n.value
+	}
+	var buf strings.Builder
+	for _, c := range n.children {
+		buf.WriteString(c.text())
+	}
+	return buf.String()
+}
+
+// descendantsOrSelf returns the node and all its descendant elements and text nodes, in document order
+func (n *xmlNode) descendantsOrSelf() []*xmlNode {
+	var nodes = []*xmlNode{n}
+	for _, c := range n.children {
+		nodes = append(nodes, c.descendantsOrSelf()...)
+	}
+	return nodes
+}
+
+// step is a single step of a location path
+type step struct {
+	descendant bool   // true for steps following a //
+	test       string // name test, like *, text(), @id, atom:entry, . or ..
+	predicates []string
+}
+
+// xpath is a compiled location path
+type xpath struct {
+	steps      []step
+	namespaces map[string]string
+}
+
+// compileXPath parses the given location path, resolving prefixes using the given namespaces
+func compileXPath(path string, namespaces map[string]string) (*xpath, error) {
+	var x = &xpath{namespaces: namespaces}
+	var rest = strings.TrimSpace(path)
+	if rest == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	for rest != "" {
+		var s step
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			s.descendant, rest = true, rest[2:]
+		case strings.HasPrefix(rest, "/"):
+			rest = rest[1:]
+		case len(x.steps) > 0:
+			return nil, fmt.Errorf("expected '/' before '%s'", rest)
+		}
+
+		var end, err = stepEnd(rest)
+		if err != nil {
+			return nil, err
+		}
+		var raw = rest[:end]
+		rest = rest[end:]
+
+		if i := strings.IndexByte(raw, '['); i >= 0 {
+			s.test = raw[:i]
+			for _, p := range strings.Split(strings.TrimSuffix(raw[i+1:], "]"), "][") {
+				s.predicates = append(s.predicates, strings.TrimSpace(p))
+			}
+		} else {
+			s.test = raw
+		}
+		if s.test = strings.TrimSpace(s.test); s.test == "" {
+			return nil, fmt.Errorf("empty step in '%s'", path)
+		}
+		if err = x.checkName(strings.TrimPrefix(s.test, "@")); err != nil {
+			return nil, err
+		}
+		x.steps = append(x.steps, s)
+	}
+	return x, nil
+}
+
+// stepEnd returns the index of the '/' that ends the step at the start of s (ignoring ones in predicates and quotes)
+func stepEnd(s string) (int, error) {
+	var depth = 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '/' && depth == 0:
+			return i, nil
+		}
+	}
+	if depth != 0 || quote != 0 {
+		return 0, fmt.Errorf("unbalanced brackets or quotes in '%s'", s)
+	}
+	return len(s), nil
+}
+
+// checkName makes sure the prefix (if any) of the given name is declared
+func (x *xpath) checkName(name string) error {
+	if i := strings.IndexByte(name, ':'); i >= 0 && name[:i] != "xml" {
+		if _, ok := x.namespaces[name[:i]]; !ok {
+			return fmt.Errorf("undeclared namespace prefix '%s'", name[:i])
+		}
+	}
+	return nil
+}
+
+// matches reports whether the (element or attribute) node's name matches the given name test
+func (x *xpath) matches(n *xmlNode, test string) bool {
+	if test == "*" {
+		return true
+	}
+	var space, local = "", test
+	if i := strings.IndexByte(test, ':'); i >= 0 {
+		space, local = x.namespaces[test[:i]], test[i+1:]
+		if test[:i] == "xml" {
+			space = "http://www.w3.org/XML/1998/namespace"
+		}
+	}
+	if local == "*" {
+		return n.name.Space == space
+	}
+	return n.name.Space == space && n.name.Local == local
+}
+
+// eval evaluates the path against the document, returning the selected nodes
+func (x *xpath) eval(doc *xmlNode) ([]*xmlNode, error) {
+	var context = []*xmlNode{doc}
+	for _, s := range x.steps {
+		var selected []*xmlNode
+		var seen = make(map[*xmlNode]bool)
+		for _, ctx := range context {
+			var parents = []*xmlNode{ctx}
+			if s.descendant {
+				parents = ctx.descendantsOrSelf()
+			}
+			for _, parent := range parents {
+				var candidates, err = x.candidates(parent, s)
+				if err != nil {
+					return nil, err
+				}
+				for _, n := range candidates {
+					if !seen[n] {
+						seen[n] = true
+						selected = append(selected, n)
+					}
+				}
+			}
+		}
+		context = selected
+	}
+	return context, nil
+}
+
+// candidates returns the nodes selected by the step from the given context node, after applying predicates
+func (x *xpath) candidates(ctx *xmlNode, s step) (nodes []*xmlNode, err error) {
+	switch {
+	case s.test == ".":
+		nodes = []*xmlNode{ctx}
+	case s.test == "..":
+		if ctx.parent != nil {
+			nodes = []*xmlNode{ctx.parent}
+		}
+	case s.test == "text()":
+		for _, c := range ctx.children {
+			if c.kind == textNode {
+				nodes = append(nodes, c)
+			}
+		}
+	case strings.HasPrefix(s.test, "@"):
+		for _, a := range ctx.attrs {
+			if x.matches(a, s.test[1:]) {
+				nodes = append(nodes, a)
+			}
+		}
+	default:
+		for _, c := range ctx.children {
+			if c.kind == elementNode && x.matches(c, s.test) {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+
+	for _, p := range s.predicates {
+		if nodes, err = x.filter(nodes, p); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// filter returns the nodes that satisfy the given predicate
+func (x *xpath) filter(nodes []*xmlNode, predicate string) ([]*xmlNode, error) {
+	if position, err := strconv.Atoi(predicate); err == nil {
+		if position < 1 || position > len(nodes) {
+			return nil, nil
+		}
+		return []*xmlNode{nodes[position-1]}, nil
+	}
+
+	// split "name='value'" into the operand and the (unquoted) value
+	var operand, value = predicate, ""
+	var compare = false
+	if i := strings.IndexByte(predicate, '='); i >= 0 {
+		operand, value, compare = strings.TrimSpace(predicate[:i]), strings.TrimSpace(predicate[i+1:]), true
+		if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] {
+			return nil, fmt.Errorf("unsupported predicate [%s]: value must be a quoted string", predicate)
+		}
+		value = value[1 : len(value)-1]
+	}
+	if strings.ContainsAny(operand, "/[]()!<> ") && operand != "text()" {
+		return nil, fmt.Errorf("unsupported predicate [%s]", predicate)
+	}
+	if err := x.checkName(strings.TrimPrefix(operand, "@")); err != nil {
+		return nil, err
+	}
+
+	var filtered []*xmlNode
+	for _, n := range nodes {
+		var operands, _ = x.candidates(n, step{test: operand})
+		for _, o := range operands {
+			if !compare || o.text() == value {
+				filtered = append(filtered, n)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
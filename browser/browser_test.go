@@ -0,0 +1,209 @@
+package browser_test
+
+import (
+	"fmt"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/browser"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/matchers"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestingT implementation that logs it's method calls
+type reporter map[string]int
+
+func (r reporter) Errorf(_ string, _ ...interface{}) { r["Errorf"] = r["Errorf"] + 1 }
+func (r reporter) FailNow()                          { r["FailNow"] = r["FailNow"] + 1 }
+func (r reporter) Helper()                           {}
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+// app is a small server-rendered application with a login flow and a few forms
+func app() http.Handler {
+	var mux = http.NewServeMux()
+	var render = func(w http.ResponseWriter, body string) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, "<!doctype html><html><body>%s</body></html>", body)
+	}
+
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if r.PostFormValue("csrf_token") != "t0k3n" || r.PostFormValue("password") != "s3cr3t" || r.Referer() == "" {
+				w.WriteHeader(http.StatusForbidden)
+				render(w, "<p class=error>Invalid credentials</p>")
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: r.PostFormValue("username"), Path: "/"})
+			http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+			return
+		}
+		render(w, `<form id="login" method="post" action="/login">
+			<input type="hidden" name="csrf_token" value="t0k3n">
+			<input name="username"><input type="password" name="password">
+			<button type="submit">Log in</button>
+		</form>`)
+	})
+
+	mux.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		var cookie, err = r.Cookie("session")
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		render(w, fmt.Sprintf(`<h1>Welcome, %s</h1><a href="logout">Log out</a> <a href="/search">Search</a>
+			<form id="checkout" method="post" action="/checkout" enctype="multipart/form-data">
+				<select name="plan"><option value="free">Free</option><option value="pro">Pro</option></select>
+				<input type="checkbox" name="terms" value="yes">
+				<button name="action" value="pay" formaction="/pay">Pay</button>
+				<input type="submit" name="action" value="save">
+			</form>`, cookie.Value))
+	})
+
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		render(w, fmt.Sprintf(`<form><input name="q" value="%s"><input type="submit" name="go" value="Go"></form>`, r.FormValue("q")))
+	})
+
+	mux.HandleFunc("/pay", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/checkout", http.StatusTemporaryRedirect)
+	})
+
+	mux.HandleFunc("/checkout", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		render(w, fmt.Sprintf("<p>%s %s %v</p>", r.Method, r.URL.Path, r.MultipartForm.Value))
+	})
+	return mux
+}
+
+func TestSession(t *testing.T) {
+	var session = browser.NewSession(WithHandler(app()))
+
+	var dashboard = session.Open("/login").
+		ExpectIt(t, ToHaveStatus(http.StatusOK), ToHaveElement("form#login")).
+		Form("form#login").Set("username", "john").Set("password", "s3cr3t").Submit().
+		ExpectIt(t, ToHaveStatus(http.StatusOK), ElementText("h1", matchers.Equal("Welcome, john")))
+	assert(t, dashboard.URL().String() == "http://localhost/dashboard", "must follow redirect after login: %s", dashboard.URL())
+
+	dashboard.Follow("Search").
+		Form("").Set("q", "shoes").Submit().
+		ExpectIt(t, FormField("form", "q", matchers.Equal("shoes")))
+
+	dashboard.Form("form#checkout").Set("plan", "pro").Set("terms", "yes").Click("button").
+		ExpectIt(t, ElementText("p", matchers.Equal("POST /checkout map[action:[pay] plan:[pro] terms:[yes]]")))
+	dashboard.Form("form#checkout").Click("input[value=save]").
+		ExpectIt(t, ElementText("p", matchers.Equal("POST /checkout map[action:[save] plan:[free]]")))
+
+	dashboard.Follow("Log out").ExpectIt(t, ToHaveElement("form#login"))
+	session.Open("/dashboard").ExpectIt(t, ToHaveElement("form#login"))
+}
+
+func TestManualRedirects(t *testing.T) {
+	var session = browser.NewSession(WithHandler(app()), browser.WithMaxRedirects(0), browser.WithBaseURL("https://example.com"))
+
+	var page = session.Open("/dashboard").ExpectIt(t, ToRedirectTo("/login"))
+	assert(t, page.IsRedirect(), "must not follow redirects")
+	page.FollowRedirect().ExpectIt(t, ToHaveStatus(http.StatusOK), ToHaveElement("form#login"))
+
+	var r = make(reporter)
+	page.FollowRedirect().FollowRedirect().ExpectIt(r)
+	assert(t, r["Errorf"] == 1 && r["FailNow"] == 1, "must fail if page is not a redirect")
+}
+
+func TestSessionErrors(t *testing.T) {
+	var session = browser.NewSession(WithHandler(app()))
+
+	var steps = map[string]func() *browser.Page{
+		"missing link":    func() *browser.Page { return session.Open("/login").Follow("Nowhere") },
+		"missing form":    func() *browser.Page { return session.Open("/login").Form("#nope").Submit() },
+		"missing field":   func() *browser.Page { return session.Open("/login").Form("").Set("email", "x").Submit() },
+		"missing button":  func() *browser.Page { return session.Open("/login").Form("").Click("input[type=image]") },
+		"invalid form":    func() *browser.Page { return session.Open("/login").Form("[").Submit() },
+		"not a redirect":  func() *browser.Page { return session.Open("/login").FollowRedirect() },
+		"failed request":  func() *browser.Page { return session.Open("http://[::1").Follow("x") },
+		"chained failure": func() *browser.Page { return session.Open("/login").Follow("x").Form("").Set("a", "b").Click("x") },
+	}
+	for name, step := range steps {
+		var r = make(reporter)
+		var page = step()
+		page.ExpectIt(r)
+		assert(t, page.Err != nil && r["Errorf"] == 1 && r["FailNow"] == 1, "%s: must report error", name)
+	}
+
+	var r = make(reporter)
+	session.Open("/login").Form("form#login").Set("username", "john").Set("password", "wrong").Submit().
+		ExpectIt(r, ToHaveStatus(http.StatusOK))
+	assert(t, r["Errorf"] == 1 && r["FailNow"] == 0, "must report failed assertions")
+
+	var json = browser.NewSession(WithHandlerFn(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	assert(t, strings.Contains(json.Open("/").Follow("x").Err.Error(), "not html"), "must fail for non-html pages")
+
+	var loop = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	var page = browser.NewSession(WithHandler(loop), browser.WithMaxRedirects(3)).Open("/")
+	assert(t, page.Err != nil && strings.Contains(page.Err.Error(), "stopped after 3 redirects"), "must fail on redirect loops: %v", page.Err)
+
+	page = browser.NewSession(WithHandler(loop), browser.WithMaxRedirects(0)).Open("/")
+	assert(t, page.Err == nil && page.IsRedirect(), "must return redirect if redirects are handled manually")
+}
+
+func TestFormOrder(t *testing.T) {
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, `<form method="%s" action="/echo" enctype="%s">
+			<input name="b" value="1"><input name="a" value="2">
+			<button name="go" value="x">Go</button>
+			<input name="b" value="3"><input type="checkbox" name="c" value="4">
+		</form>`, r.FormValue("method"), r.FormValue("enctype"))
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		var pairs []string
+		switch {
+		case r.Method == http.MethodGet:
+			pairs = []string{r.URL.RawQuery}
+		case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"):
+			var reader, _ = r.MultipartReader()
+			for part, err := reader.NextPart(); err == nil; part, err = reader.NextPart() {
+				var buf strings.Builder
+				_, _ = io.Copy(&buf, part)
+				pairs = append(pairs, part.FormName()+"="+buf.String())
+			}
+		default:
+			var body, _ = ioutil.ReadAll(r.Body)
+			pairs = []string{strings.TrimSpace(string(body))}
+		}
+		_, _ = fmt.Fprintf(w, "<p>%s</p>", html.EscapeString(strings.Join(pairs, "&")))
+	})
+
+	var session = browser.NewSession(WithHandler(mux))
+	for query, expected := range map[string]string{
+		"?method=get":  "b=1&a=2&go=x&b=9&c=4",
+		"?method=post": "b=1&a=2&go=x&b=9&c=4",
+		"?method=post&enctype=multipart/form-data": "b=1&a=2&go=x&b=9&c=4",
+		"?method=post&enctype=text/plain":          "b=1 a=2 go=x b=9 c=4",
+	} {
+		session.Open("/"+query).Form("").Set("c", "4").Set("b", "1", "9").Click("button").
+			ExpectIt(t, ElementText("p", matchers.Equal(expected)))
+	}
+}
@@ -0,0 +1,204 @@
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"go.riyazali.net/httpx/dom"
+	"golang.org/x/net/html"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Form is an html form on a page, that can be filled and submitted.
+type Form struct {
+	page    *Page
+	node    *html.Node
+	fields  []*html.Node // the form's fields, in document order
+	entries []entry      // the values to submit, in document order
+	err     error
+}
+
+// entry is a single name / value pair submitted by a form
+type entry struct {
+	name, value string
+	pos         int // position of the field in the form, used to keep entries in document order
+}
+
+// newForm returns a Form for the given form element, pre-filled with its current values
+func newForm(page *Page, node *html.Node) *Form {
+	var form = &Form{page: page, node: node, fields: dom.FormFields(node)}
+	for i, field := range form.fields {
+		var name, _ = dom.Attr(field, "name")
+		for _, v := range dom.FieldValues(field) {
+			form.entries = append(form.entries, entry{name: name, value: v, pos: i})
+		}
+	}
+	return form
+}
+
+// Set sets the values submitted for the named field, replacing its current values. Use it for all kinds of fields,
+// ie. to type into text fields, choose options in a select, or check checkboxes and radio buttons (by setting their value).
+// Calling it without any values clears the field (say, to uncheck a checkbox). The form must have a field with the name.
+//
+// The values are assigned to the fields with the name in document order, with any values left over (say, for a
+// multiple select) going to the last of them, so that the form is submitted in document order, just like a browser would.
+func (f *Form) Set(name string, values ...string) *Form {
+	if f.err != nil {
+		return f
+	}
+
+	var positions []int
+	for i, field := range f.fields {
+		if n, _ := dom.Attr(field, "name"); n == name {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) == 0 {
+		f.err = f.page.fail(fmt.Errorf("form has no field named '%s'", name)).Err
+		return f
+	}
+
+	var entries []entry
+	for _, e := range f.entries {
+		if e.name != name {
+			entries = append(entries, e)
+		}
+	}
+	for i, v := range values {
+		var pos = positions[len(positions)-1]
+		if i < len(positions) {
+			pos = positions[i]
+		}
+		entries = insert(entries, entry{name: name, value: v, pos: pos})
+	}
+	f.entries = entries
+	return f
+}
+
+// Values returns a copy of the values that would be submitted
+func (f *Form) Values() url.Values {
+	var values = make(url.Values)
+	for _, e := range f.entries {
+		values.Add(e.name, e.value)
+	}
+	return values
+}
+
+// Submit submits the form (as if the user pressed enter in one of its fields) and returns the resulting page.
+// As a browser would, it honours the form's method, action and enctype (application/x-www-form-urlencoded,
+// multipart/form-data or text/plain).
+func (f *Form) Submit() *Page {
+	return f.submit(nil)
+}
+
+// Click submits the form using the first submit button (a <button> or an <input> of type submit or image) matching the
+// given selector within the form. The button's name and value are submitted along with the form, and its formaction,
+// formmethod and formenctype attributes (if any) override those of the form.
+func (f *Form) Click(selector string) *Page {
+	if f.err != nil {
+		return &Page{session: f.page.session, Err: f.err}
+	}
+
+	var sel, err = dom.CompileSelector(selector)
+	if err != nil {
+		return f.page.fail(err)
+	}
+	for _, n := range sel.Select(f.node) {
+		var kind, _ = dom.Attr(n, "type")
+		kind = strings.ToLower(kind)
+		if (n.Data == "button" && (kind == "" || kind == "submit")) || (n.Data == "input" && (kind == "submit" || kind == "image")) {
+			return f.submit(n)
+		}
+	}
+	return f.page.fail(fmt.Errorf("no submit button matches '%s'", selector))
+}
+
+// submit submits the form using the (optional) submitter
+func (f *Form) submit(submitter *html.Node) *Page {
+	if f.err != nil {
+		return &Page{session: f.page.session, Err: f.err}
+	}
+
+	var entries = append([]entry(nil), f.entries...)
+	var attr = func(name string) string {
+		if submitter != nil {
+			if v, ok := dom.Attr(submitter, "form"+name); ok {
+				return v
+			}
+		}
+		var v, _ = dom.Attr(f.node, name)
+		return v
+	}
+	if submitter != nil {
+		if name, _ := dom.Attr(submitter, "name"); name != "" {
+			var value, _ = dom.Attr(submitter, "value")
+			var pos = len(f.fields)
+			for i, field := range f.fields {
+				if field == submitter {
+					pos = i
+				}
+			}
+			entries = insert(entries, entry{name: name, value: value, pos: pos})
+		}
+	}
+
+	var action, err = f.page.URL().Parse(strings.TrimSpace(attr("action")))
+	if err != nil {
+		return f.page.fail(fmt.Errorf("invalid form action '%s': %v", attr("action"), err))
+	}
+
+	if !strings.EqualFold(attr("method"), http.MethodPost) {
+		action.RawQuery = urlencode(entries)
+		return f.page.navigate(http.MethodGet, action, nil, "")
+	}
+
+	var body []byte
+	var contentType string
+	switch enctype := strings.ToLower(attr("enctype")); enctype {
+	case "multipart/form-data":
+		var buf bytes.Buffer
+		var writer = multipart.NewWriter(&buf)
+		for _, e := range entries {
+			_ = writer.WriteField(e.name, e.value) // writes to a buffer can't fail
+		}
+		_ = writer.Close()
+		body, contentType = buf.Bytes(), writer.FormDataContentType()
+	case "text/plain":
+		var buf bytes.Buffer
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(&buf, "%s=%s\r\n", e.name, e.value)
+		}
+		body, contentType = buf.Bytes(), "text/plain"
+	default:
+		body, contentType = []byte(urlencode(entries)), "application/x-www-form-urlencoded"
+	}
+	return f.page.navigate(http.MethodPost, action, body, contentType)
+}
+
+// insert inserts the entry after all the entries of fields preceding (or equal to) its field
+func insert(entries []entry, e entry) []entry {
+	var i = len(entries)
+	for i > 0 && entries[i-1].pos > e.pos {
+		i--
+	}
+	entries = append(entries, entry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	return entries
+}
+
+// urlencode encodes the entries as application/x-www-form-urlencoded, in order (unlike url.Values.Encode())
+func urlencode(entries []entry) string {
+	var buf strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(url.QueryEscape(e.name))
+		buf.WriteByte('=')
+		buf.WriteString(url.QueryEscape(e.value))
+	}
+	return buf.String()
+}
@@ -0,0 +1,210 @@
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/dom"
+	"golang.org/x/net/html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// Page is the result of a navigation step in a Session.
+type Page struct {
+	session    *Session
+	assertable httpx.Assertable
+	doc        *html.Node
+
+	// Request that was sent (after following redirects), and the Response that was received, with its buffered Body
+	Request  *http.Request
+	Response *http.Response
+	Body     []byte
+
+	// Err is set if the page couldn't be loaded (or a prior step in the chain failed)
+	Err error
+}
+
+// URL returns the url of the page
+func (p *Page) URL() *url.URL {
+	if p.Request == nil {
+		return nil
+	}
+	return p.Request.URL
+}
+
+// ExpectIt runs the given assertions on the page's response and reports failures to t, just like
+// MakeRequest(...).ExpectIt(...) would. If the page has an error, it is reported and the test is stopped.
+// It returns the page itself, so that navigation can continue.
+func (p *Page) ExpectIt(t httpx.TestingT, assertions ...httpx.Assertion) *Page {
+	t.Helper()
+	if p.Err != nil {
+		t.Errorf("%v", p.Err)
+		t.FailNow() // doesn't return
+		return p
+	}
+	p.assertable.ExpectIt(t, assertions...)
+	return p
+}
+
+// IsRedirect returns true if the page's response is a redirect (301, 302, 303, 307 or 308) with a Location.
+func (p *Page) IsRedirect() bool {
+	if p.Response == nil || p.Response.Header.Get("Location") == "" {
+		return false
+	}
+	switch p.Response.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// FollowRedirect follows the page's redirect (without following any further redirects) and returns the resulting page.
+// Use it along with WithMaxRedirects(0) to inspect each redirect in a chain.
+func (p *Page) FollowRedirect() *Page {
+	if p.Err != nil {
+		return p
+	}
+	if !p.IsRedirect() {
+		return p.fail(fmt.Errorf("response is not a redirect (%d)", p.Response.StatusCode))
+	}
+
+	var next, err = p.redirect()
+	if err != nil {
+		return p.fail(err)
+	}
+	return p.session.navigate(next, nil, 0)
+}
+
+// Follow follows the first link (an <a> element) with the given text and returns the resulting page.
+// The text is compared after collapsing whitespace (see dom.Text(...)).
+func (p *Page) Follow(text string) *Page {
+	var doc, err = p.document()
+	if err != nil {
+		return p.fail(err)
+	}
+
+	for _, link := range mustCompile("a[href]").Select(doc) {
+		if dom.Text(link) == text {
+			var href, _ = dom.Attr(link, "href")
+			var target, err = p.URL().Parse(href)
+			if err != nil {
+				return p.fail(fmt.Errorf("invalid href '%s': %v", href, err))
+			}
+			return p.navigate(http.MethodGet, target, nil, "")
+		}
+	}
+	return p.fail(fmt.Errorf("no link with text '%s'", text))
+}
+
+// Form returns the first form matching the given selector (see dom.CompileSelector(...)), or the first form on
+// the page if the selector is empty. Errors (like no matching form) are reported when the form is submitted.
+func (p *Page) Form(selector string) *Form {
+	if selector == "" {
+		selector = "form"
+	}
+
+	var doc, err = p.document()
+	if err != nil {
+		return &Form{page: p, err: p.fail(err).Err}
+	}
+
+	var sel dom.Selector
+	if sel, err = dom.CompileSelector(selector); err != nil {
+		return &Form{page: p, err: p.fail(err).Err}
+	}
+	for _, n := range sel.Select(doc) {
+		if n.Data == "form" {
+			return newForm(p, n)
+		}
+	}
+	return &Form{page: p, err: p.fail(fmt.Errorf("no form matches '%s'", selector)).Err}
+}
+
+// document parses (and caches) the page's body as html
+func (p *Page) document() (*html.Node, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	if p.doc != nil {
+		return p.doc, nil
+	}
+
+	if ct := p.Response.Header.Get("Content-Type"); ct != "" {
+		if mediaType, _, _ := mime.ParseMediaType(ct); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			return nil, fmt.Errorf("page is not html (%s)", ct)
+		}
+	}
+
+	var err error
+	if p.doc, err = html.Parse(bytes.NewReader(p.Body)); err != nil {
+		return nil, fmt.Errorf("failed to parse page: %v", err)
+	}
+	return p.doc, nil
+}
+
+// navigate makes a request from this page (setting the Referer) and returns the resulting page
+func (p *Page) navigate(method string, target *url.URL, body []byte, contentType string) *Page {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	var factory = httpx.Using(method, target.String(), reader)
+	return p.session.Do(factory, func(request *http.Request) error {
+		request.Header.Set("Referer", p.URL().String())
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		return nil
+	})
+}
+
+// redirect returns a RequestFactory for the request a browser would make to follow the page's redirect
+func (p *Page) redirect() (httpx.RequestFactory, error) {
+	var location = p.Response.Header.Get("Location")
+	var target, err = p.URL().Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("browser: invalid redirect location '%s': %v", location, err)
+	}
+
+	// 307 and 308 preserve the method and body, whereas browsers change the method to GET for others
+	var method, original = http.MethodGet, p.Request
+	var preserve = p.Response.StatusCode == http.StatusTemporaryRedirect || p.Response.StatusCode == http.StatusPermanentRedirect
+	if preserve || original.Method == http.MethodHead {
+		method = original.Method
+	}
+	if preserve && original.GetBody == nil && original.ContentLength != 0 {
+		return nil, fmt.Errorf("browser: cannot follow %d redirect to %s: request body cannot be re-sent", p.Response.StatusCode, target)
+	}
+
+	return func() (*http.Request, error) {
+		var request, err = http.NewRequest(method, target.String(), nil)
+		if err == nil && preserve && original.GetBody != nil {
+			if request.Body, err = original.GetBody(); err == nil {
+				request.GetBody, request.ContentLength = original.GetBody, original.ContentLength
+				request.Header.Set("Content-Type", original.Header.Get("Content-Type"))
+			}
+		}
+		return request, err
+	}, nil
+}
+
+// fail returns a page with the given error, annotated with the url of this page
+func (p *Page) fail(err error) *Page {
+	if p.Err != nil {
+		return p
+	}
+	return &Page{session: p.session, Err: fmt.Errorf("browser: %s: %v", p.URL(), err)}
+}
+
+// mustCompile compiles the given selector, panicking on error
+func mustCompile(selector string) dom.Selector {
+	var sel, err = dom.CompileSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
@@ -0,0 +1,129 @@
+// Package browser provides a lightweight, browser-like session for testing server-rendered html applications.
+//
+// A Session keeps cookies across requests (using a cookie jar), follows redirects and lets you navigate
+// between pages the way a user would, by following links and filling and submitting forms,
+//
+//  var session = browser.NewSession(WithHandler(handler))
+//  session.Open("/login").
+//    ExpectIt(t, ToHaveStatus(http.StatusOK)).
+//    Form("form#login").Set("username", "john").Set("password", "s3cr3t").Submit().
+//    ExpectIt(t, ElementText("h1", matchers.Equal("Welcome, john"))).
+//    Follow("Log out").
+//    ExpectIt(t, ToHaveElement("form#login"))
+//
+// Every step returns a Page (or a Form) and so steps can be chained. If a step fails (say, a link isn't found),
+// the error is carried along the chain and reported by the next ExpectIt(...).
+package browser // import "go.riyazali.net/httpx/browser"
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+)
+
+// Session is a browser-like session that makes requests using an ExecFn.
+type Session struct {
+	fn httpx.ExecFn
+
+	// Jar stores cookies received in responses and adds them to subsequent requests
+	Jar http.CookieJar
+
+	// Base is the url relative request urls are resolved against (defaults to http://localhost)
+	Base *url.URL
+
+	// MaxRedirects is the maximum number of redirects followed automatically. A page that still redirects
+	// after that many fails with an error. Set it to zero to handle redirects manually using Page.FollowRedirect().
+	MaxRedirects int
+}
+
+// NewSession returns a new Session that makes requests using the given ExecFn.
+func NewSession(fn httpx.ExecFn, opts ...func(*Session)) *Session {
+	var jar, _ = cookiejar.New(nil) // never returns an error
+	var session = &Session{fn: fn, Jar: jar, Base: &url.URL{Scheme: "http", Host: "localhost", Path: "/"}, MaxRedirects: 10}
+	for _, opt := range opts {
+		opt(session)
+	}
+	return session
+}
+
+// WithBaseURL sets the url relative request urls are resolved against. Panics if the url cannot be parsed.
+func WithBaseURL(base string) func(*Session) {
+	var u, err = url.Parse(base)
+	if err != nil {
+		panic(fmt.Sprintf("browser: invalid base url: %v", err))
+	}
+	return func(s *Session) {
+		s.Base = u
+	}
+}
+
+// WithMaxRedirects sets the maximum number of redirects followed automatically (see Session.MaxRedirects).
+func WithMaxRedirects(n int) func(*Session) {
+	return func(s *Session) {
+		s.MaxRedirects = n
+	}
+}
+
+// WithJar sets the cookie jar used by the session.
+func WithJar(jar http.CookieJar) func(*Session) {
+	return func(s *Session) {
+		s.Jar = jar
+	}
+}
+
+// Open makes a GET request to the given url and returns the resulting page.
+func (s *Session) Open(url string) *Page {
+	return s.Do(httpx.Get(url))
+}
+
+// Do makes a request using the given RequestFactory and RequestBuilders, following redirects, and returns the resulting page.
+// The builders are applied to the initial request only (and not to the requests made when following redirects).
+func (s *Session) Do(factory httpx.RequestFactory, builders ...httpx.RequestBuilder) *Page {
+	return s.navigate(factory, builders, s.MaxRedirects)
+}
+
+// navigate makes the request, following up to the given number of redirects. If the limit is reached in the middle
+// of a chain of redirects, it fails (unless no redirects were to be followed at all, in which case the redirect is returned).
+func (s *Session) navigate(factory httpx.RequestFactory, builders []httpx.RequestBuilder, redirects int) *Page {
+	var limit = redirects
+	for {
+		var assertable = s.fn.MakeRequest(factory, append([]httpx.RequestBuilder{s.prepare}, builders...)...)
+		var result = assertable.Evaluate()
+		if result.Err != nil {
+			return &Page{session: s, Err: result.Err}
+		}
+		if s.Jar != nil {
+			s.Jar.SetCookies(result.Request.URL, result.Response.Cookies())
+		}
+
+		var page = &Page{session: s, assertable: assertable, Request: result.Request, Response: result.Response, Body: result.Body}
+		if !page.IsRedirect() || limit <= 0 {
+			return page
+		} else if redirects <= 0 {
+			return page.fail(fmt.Errorf("stopped after %d redirects", limit))
+		}
+		redirects--
+
+		var next, err = page.redirect()
+		if err != nil {
+			return &Page{session: s, Err: err}
+		}
+		factory, builders = next, nil
+	}
+}
+
+// prepare resolves the request url against the base url and adds cookies from the jar
+func (s *Session) prepare(request *http.Request) error {
+	if !request.URL.IsAbs() {
+		request.URL = s.Base.ResolveReference(request.URL)
+		request.Host = request.URL.Host
+	}
+	if s.Jar != nil {
+		for _, cookie := range s.Jar.Cookies(request.URL) {
+			request.AddCookie(cookie)
+		}
+	}
+	return nil
+}
@@ -2,9 +2,17 @@
 package builders // import "go.riyazali.net/httpx/builders"
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"go.riyazali.net/httpx"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 )
 
 // WithHeader takes in a header name and one or more values and returns a RequestBuilder.
@@ -47,3 +55,94 @@ func WithHost(host string) httpx.RequestBuilder {
 		return nil
 	}
 }
+
+// WithOrigin sets the Origin header on the request, making it a cross-origin (CORS) request.
+func WithOrigin(origin string) httpx.RequestBuilder {
+	return WithHeader("Origin", origin)
+}
+
+// WithPreflight turns the request into a CORS preflight request for an actual request with the given origin,
+// method and (non-safelisted) headers. Use it with httpx.Options(...), like,
+//
+//  MakeRequest(Options("/api/users"), WithPreflight("https://example.com", http.MethodPut, "Content-Type", "X-Token"))
+//
+// As browsers do, header names are lower-cased, sorted and sent as a single comma-separated value.
+func WithPreflight(origin, method string, headers ...string) httpx.RequestBuilder {
+	return func(request *http.Request) error {
+		if request.Method != http.MethodOptions {
+			return fmt.Errorf("preflight: request method must be OPTIONS, got %s", request.Method)
+		}
+		request.Header.Set("Origin", origin)
+		request.Header.Set("Access-Control-Request-Method", method)
+		if len(headers) > 0 {
+			var names = make([]string, len(headers))
+			for i, h := range headers {
+				names[i] = strings.ToLower(h)
+			}
+			sort.Strings(names)
+			request.Header.Set("Access-Control-Request-Headers", strings.Join(names, ","))
+		}
+		return nil
+	}
+}
+
+// WithIfNoneMatch sets the If-None-Match header to the given entity-tags (use "*" to match any),
+// making the request conditional on the resource having changed.
+func WithIfNoneMatch(etag string, etags ...string) httpx.RequestBuilder {
+	return WithHeader("If-None-Match", strings.Join(append([]string{etag}, etags...), ", "))
+}
+
+// WithIfMatch sets the If-Match header to the given entity-tags (use "*" to match any),
+// making the request conditional on the resource not having changed (say, to prevent lost updates).
+func WithIfMatch(etag string, etags ...string) httpx.RequestBuilder {
+	return WithHeader("If-Match", strings.Join(append([]string{etag}, etags...), ", "))
+}
+
+// WithIfModifiedSince sets the If-Modified-Since header to the given time.
+func WithIfModifiedSince(t time.Time) httpx.RequestBuilder {
+	return WithHeader("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// WithIfUnmodifiedSince sets the If-Unmodified-Since header to the given time.
+func WithIfUnmodifiedSince(t time.Time) httpx.RequestBuilder {
+	return WithHeader("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// WithForm sets the url-encoded form values as the request body (with the application/x-www-form-urlencoded content type),
+// replacing any existing body.
+func WithForm(values url.Values) httpx.RequestBuilder {
+	return func(request *http.Request) error {
+		setBody(request, []byte(values.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return nil
+	}
+}
+
+// WithJson serializes the given object using encoding/json and sets it as the request body (with the application/json
+// content type), replacing any existing body. The builder fails if the object cannot be serialized.
+func WithJson(obj interface{}) httpx.RequestBuilder {
+	return func(request *http.Request) error {
+		var body, err = json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("json: failed to serialize body: %v", err)
+		}
+		setBody(request, body)
+		request.Header.Set("Content-Type", "application/json")
+		return nil
+	}
+}
+
+// WithBody sets the given string as the request body, replacing any existing body.
+func WithBody(body string) httpx.RequestBuilder {
+	return func(request *http.Request) error {
+		setBody(request, []byte(body))
+		return nil
+	}
+}
+
+// setBody sets body as the request body, allowing it to be re-read (eg. on redirects)
+func setBody(request *http.Request, body []byte) {
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(body)), nil }
+	request.ContentLength = int64(len(body))
+}
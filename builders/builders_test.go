@@ -2,8 +2,11 @@ package builders
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
@@ -75,3 +78,68 @@ func TestWithHost(t *testing.T) {
 	require(t, err == nil, "builder must not return error")
 	assert(t, r.Host == "httpbin.org", "host must be overridden")
 }
+
+func TestWithOrigin(t *testing.T) {
+	var r = newRequest()
+	require(t, WithOrigin("https://example.com")(r) == nil, "builder must not return error")
+	assert(t, r.Header.Get("Origin") == "https://example.com", "must set Origin header")
+}
+
+func TestWithPreflight(t *testing.T) {
+	var r, _ = http.NewRequest(http.MethodOptions, "/", nil)
+	var err = WithPreflight("https://example.com", http.MethodPut, "X-Token", "Content-Type")(r)
+	require(t, err == nil, "builder must not return error")
+	assert(t, r.Header.Get("Origin") == "https://example.com", "must set Origin header")
+	assert(t, r.Header.Get("Access-Control-Request-Method") == http.MethodPut, "must set requested method")
+	assert(t, r.Header.Get("Access-Control-Request-Headers") == "content-type,x-token", "must set sorted, lower-cased headers")
+
+	var g = newRequest()
+	assert(t, WithPreflight("https://example.com", http.MethodPut)(g) != nil, "must fail for non-OPTIONS request")
+}
+
+func TestConditionalHeaders(t *testing.T) {
+	var r = newRequest()
+	var ts = time.Date(2020, 6, 1, 10, 0, 0, 0, time.FixedZone("IST", 19800))
+
+	require(t, WithIfNoneMatch(`"a"`, `W/"b"`)(r) == nil, "builder must not return error")
+	require(t, WithIfMatch("*")(r) == nil, "builder must not return error")
+	require(t, WithIfModifiedSince(ts)(r) == nil, "builder must not return error")
+	require(t, WithIfUnmodifiedSince(ts)(r) == nil, "builder must not return error")
+
+	assert(t, r.Header.Get("If-None-Match") == `"a", W/"b"`, "must set all entity-tags")
+	assert(t, r.Header.Get("If-Match") == "*", "must set If-Match")
+	assert(t, r.Header.Get("If-Modified-Since") == "Mon, 01 Jun 2020 04:30:00 GMT", "must format time in GMT")
+	assert(t, r.Header.Get("If-Unmodified-Since") == "Mon, 01 Jun 2020 04:30:00 GMT", "must format time in GMT")
+}
+
+func TestWithForm(t *testing.T) {
+	var r, _ = http.NewRequest(http.MethodPost, "/", nil)
+	require(t, WithForm(url.Values{"name": {"john doe"}, "tag": {"a", "b"}})(r) == nil, "builder must not return error")
+	assert(t, r.Header.Get("Content-Type") == "application/x-www-form-urlencoded", "must set content type")
+	require(t, r.ParseForm() == nil, "must encode a valid form")
+	assert(t, r.PostForm.Get("name") == "john doe" && len(r.PostForm["tag"]) == 2, "must encode all values")
+
+	var body, _ = r.GetBody()
+	var b, _ = ioutil.ReadAll(body)
+	assert(t, int64(len(b)) == r.ContentLength, "must allow body to be re-read")
+}
+
+func TestWithJson(t *testing.T) {
+	var r, _ = http.NewRequest(http.MethodPost, "/", nil)
+	require(t, WithJson(map[string]int{"qty": 2})(r) == nil, "builder must not return error")
+	assert(t, r.Header.Get("Content-Type") == "application/json", "must set content type")
+
+	var b, _ = ioutil.ReadAll(r.Body)
+	assert(t, string(b) == `{"qty":2}` && int64(len(b)) == r.ContentLength, "must serialize object")
+
+	assert(t, WithJson(func() {})(r) != nil, "must fail if object cannot be serialized")
+}
+
+func TestWithBody(t *testing.T) {
+	var r, _ = http.NewRequest(http.MethodPost, "/", nil)
+	require(t, WithBody("hello")(r) == nil, "builder must not return error")
+
+	var body, _ = r.GetBody()
+	var b, _ = ioutil.ReadAll(body)
+	assert(t, string(b) == "hello" && r.ContentLength == 5, "must set body")
+}
@@ -0,0 +1,138 @@
+// Package dom provides helpers to query parsed html documents, using a subset of CSS selectors
+// (see CompileSelector(...)) and to extract values a browser would submit for a form.
+//
+// It is used by the html assertions in the assertions package and by the browser package. Note that these
+// packages (and so their users) depend on golang.org/x/net/html, whereas the root httpx package doesn't.
+package dom // import "go.riyazali.net/httpx/dom"
+
+import (
+	"golang.org/x/net/html"
+	"net/url"
+	"strings"
+)
+
+// Attr returns the value of the given attribute of the node, and false if the node doesn't have it.
+func Attr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text content of the node, with runs of whitespace collapsed into a single space
+// and leading and trailing whitespace removed (roughly, the text as rendered by a browser).
+func Text(n *html.Node) string {
+	var buf strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+			buf.WriteByte(' ')
+		}
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// FormValues returns the values a browser would submit for the given form element, ie. the values of its enabled,
+// named input, select and textarea fields, excluding unchecked checkboxes and radio buttons, and buttons
+// (as no button was used to submit it). Fields outside the form (associated using the form attribute) are not included.
+func FormValues(form *html.Node) url.Values {
+	var values = make(url.Values)
+	for _, field := range FormFields(form) {
+		var name, _ = Attr(field, "name")
+		for _, v := range FieldValues(field) {
+			values.Add(name, v)
+		}
+	}
+	return values
+}
+
+// FormFields returns the enabled, named input, select, textarea and button elements of the form, in document order.
+func FormFields(form *html.Node) (fields []*html.Node) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				var name, named = Attr(c, "name")
+				var _, disabled = Attr(c, "disabled")
+				switch c.Data {
+				case "input", "select", "textarea", "button":
+					if named && name != "" && !disabled {
+						fields = append(fields, c)
+					}
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(form)
+	return fields
+}
+
+// FieldValues returns the values a browser would submit for the given (enabled, named) field, in order,
+// and no values for buttons, as no button was used to submit its form. See FormValues(...)
+func FieldValues(field *html.Node) []string {
+	switch field.Data {
+	case "textarea":
+		var buf strings.Builder
+		for c := field.FirstChild; c != nil; c = c.NextSibling {
+			buf.WriteString(c.Data)
+		}
+		return []string{strings.TrimPrefix(buf.String(), "\n")}
+	case "select":
+		var options, selected []string
+		var _, multiple = Attr(field, "multiple")
+		for _, o := range mustCompile("option").Select(field) {
+			var v, ok = Attr(o, "value")
+			if !ok {
+				v = Text(o)
+			}
+			options = append(options, v)
+			if _, ok := Attr(o, "selected"); ok {
+				selected = append(selected, v)
+			}
+		}
+		if len(selected) == 0 && !multiple && len(options) > 0 {
+			return options[:1] // the first option is selected by default
+		}
+		if !multiple && len(selected) > 1 {
+			return selected[len(selected)-1:]
+		}
+		return selected
+	case "button":
+		return nil
+	}
+
+	var kind, _ = Attr(field, "type")
+	var value, hasValue = Attr(field, "value")
+	switch strings.ToLower(kind) {
+	case "checkbox", "radio":
+		if _, checked := Attr(field, "checked"); !checked {
+			return nil
+		}
+		if !hasValue {
+			value = "on"
+		}
+	case "submit", "reset", "button", "image", "file":
+		return nil
+	}
+	return []string{value}
+}
+
+// mustCompile compiles the given selector, panicking on error
+func mustCompile(selector string) Selector {
+	var sel, err = CompileSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
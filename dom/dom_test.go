@@ -0,0 +1,45 @@
+package dom
+
+import (
+	"golang.org/x/net/html"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+func TestFormValues(t *testing.T) {
+	var doc, _ = html.Parse(strings.NewReader(`<form>
+		<input type="hidden" name="csrf" value="abc">
+		<input name="user" value=" john ">
+		<input name="disabled" value="x" disabled>
+		<input type="checkbox" name="remember">
+		<input type="checkbox" name="terms" checked>
+		<input type="radio" name="plan" value="free">
+		<input type="radio" name="plan" value="pro" checked>
+		<select name="country"><option value="in">India</option><option>  United   Kingdom </option></select>
+		<select name="tags" multiple><option selected>a</option><option>b</option><option selected>c</option></select>
+		<textarea name="bio">
+hello</textarea>
+		<input type="submit" name="go" value="Go">
+		<button name="action" value="save">Save</button>
+	</form>`))
+
+	var form = mustCompile("form").Select(doc)[0]
+	var values = FormValues(form)
+	var expected = map[string][]string{
+		"csrf": {"abc"}, "user": {" john "}, "terms": {"on"}, "plan": {"pro"},
+		"country": {"in"}, "tags": {"a", "c"}, "bio": {"hello"},
+	}
+	assert(t, reflect.DeepEqual(map[string][]string(values), expected), "must collect form values: %v", values)
+	assert(t, len(FormFields(form)) == 11, "must return enabled, named fields")
+
+	var attr, ok = Attr(mustCompile("input").Select(doc)[0], "TYPE")
+	assert(t, ok && attr == "hidden", "must look up attributes case-insensitively")
+}
@@ -0,0 +1,379 @@
+package dom
+
+import (
+	"fmt"
+	"golang.org/x/net/html"
+	"strconv"
+	"strings"
+)
+
+// Selector is a compiled CSS selector. See CompileSelector(...) for the supported syntax.
+type Selector []complexSelector
+
+// CompileSelector compiles the given CSS selector. A subset of CSS Selectors Level 3 is supported,
+//
+//    - type (div), universal (*), id (#main) and class (.item) selectors
+//    - attribute selectors ([name], [name=v], [name~=v], [name^=v], [name$=v], [name*=v], [name|=v])
+//    - :first-child, :last-child, :nth-child(n), :checked and :disabled pseudo-classes
+//    - descendant (a b), child (a > b), adjacent sibling (a + b) and general sibling (a ~ b) combinators
+//    - selector groups (a, b)
+func CompileSelector(selector string) (Selector, error) {
+	var p = &selectorParser{s: selector}
+	var sel Selector
+	for {
+		var c, err = p.complex()
+		if err != nil {
+			return nil, fmt.Errorf("selector: %v in '%s'", err, selector)
+		}
+		sel = append(sel, c)
+		if p.pos >= len(p.s) {
+			return sel, nil
+		}
+		p.pos++ // skip the comma
+	}
+}
+
+// Select returns all the elements under root (excluding root itself) that match the selector, in document order.
+func (sel Selector) Select(root *html.Node) (nodes []*html.Node) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && sel.Match(c) {
+				nodes = append(nodes, c)
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// Match returns true if the given element matches the selector
+func (sel Selector) Match(n *html.Node) bool {
+	for _, c := range sel {
+		if c.match(n, len(c.parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// complexSelector is a sequence of compound selectors separated by combinators,
+// where combinators[i] is the combinator between parts[i] and parts[i+1]
+type complexSelector struct {
+	parts       []compoundSelector
+	combinators []byte
+}
+
+// match matches the element against the i-th part, and the parts before it using the combinators (right to left)
+func (c complexSelector) match(n *html.Node, i int) bool {
+	if !c.parts[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch c.combinators[i-1] {
+	case '>':
+		return isElement(n.Parent) && c.match(n.Parent, i-1)
+	case '+':
+		var prev = previousElement(n)
+		return prev != nil && c.match(prev, i-1)
+	case '~':
+		for prev := previousElement(n); prev != nil; prev = previousElement(prev) {
+			if c.match(prev, i-1) {
+				return true
+			}
+		}
+	default: // descendant
+		for p := n.Parent; isElement(p); p = p.Parent {
+			if c.match(p, i-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compoundSelector is a sequence of simple selectors that must all match the same element
+type compoundSelector struct {
+	tag     string
+	attrs   []attributeSelector
+	pseudos []pseudoClass
+}
+
+func (c compoundSelector) match(n *html.Node) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != n.Data {
+		return false
+	}
+	for _, a := range c.attrs {
+		if !a.match(n) {
+			return false
+		}
+	}
+	for _, p := range c.pseudos {
+		if !p.match(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeSelector matches an element's attribute using the given operator (empty to check presence)
+type attributeSelector struct {
+	name, op, value string
+}
+
+func (a attributeSelector) match(n *html.Node) bool {
+	var v, ok = Attr(n, a.name)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == a.value
+	case "~=":
+		for _, f := range strings.Fields(v) {
+			if f == a.value {
+				return true
+			}
+		}
+		return false
+	case "^=":
+		return a.value != "" && strings.HasPrefix(v, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(v, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(v, a.value)
+	case "|=":
+		return v == a.value || strings.HasPrefix(v, a.value+"-")
+	}
+	return false
+}
+
+// pseudoClass matches structural or form state pseudo-classes
+type pseudoClass struct {
+	name string
+	n    int
+}
+
+func (p pseudoClass) match(n *html.Node) bool {
+	switch p.name {
+	case "first-child":
+		return previousElement(n) == nil
+	case "last-child":
+		return nextElement(n) == nil
+	case "nth-child":
+		var position = 1
+		for prev := previousElement(n); prev != nil; prev = previousElement(prev) {
+			position++
+		}
+		return position == p.n
+	case "checked":
+		var _, checked = Attr(n, "checked")
+		var _, selected = Attr(n, "selected")
+		return (n.Data == "input" && checked) || (n.Data == "option" && selected)
+	case "disabled":
+		var _, disabled = Attr(n, "disabled")
+		return disabled
+	}
+	return false
+}
+
+func isElement(n *html.Node) bool { return n != nil && n.Type == html.ElementNode }
+
+func previousElement(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+// selectorParser is a simple recursive-descent parser for css selectors
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (p *selectorParser) eof() bool { return p.pos >= len(p.s) || p.s[p.pos] == ',' }
+
+func (p *selectorParser) whitespace() bool {
+	var start = p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// complex parses a complex selector, up to the end of the string or a comma
+func (p *selectorParser) complex() (c complexSelector, err error) {
+	p.whitespace()
+	for {
+		var compound compoundSelector
+		if compound, err = p.compound(); err != nil {
+			return c, err
+		}
+		c.parts = append(c.parts, compound)
+
+		var ws = p.whitespace()
+		if p.eof() {
+			return c, nil
+		}
+		switch ch := p.s[p.pos]; {
+		case ch == '>' || ch == '+' || ch == '~':
+			c.combinators = append(c.combinators, ch)
+			p.pos++
+			p.whitespace()
+		case ws:
+			c.combinators = append(c.combinators, ' ')
+		default:
+			return c, fmt.Errorf("unexpected '%c' at position %d", ch, p.pos)
+		}
+	}
+}
+
+// compound parses a compound selector, ie. a sequence of simple selectors without whitespace between them
+func (p *selectorParser) compound() (c compoundSelector, err error) {
+	var start = p.pos
+	if p.pos < len(p.s) && p.s[p.pos] == '*' {
+		c.tag, p.pos = "*", p.pos+1
+	} else {
+		c.tag = strings.ToLower(p.ident())
+	}
+
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			var id = p.ident()
+			if id == "" {
+				return c, fmt.Errorf("expected id at position %d", p.pos)
+			}
+			c.attrs = append(c.attrs, attributeSelector{name: "id", op: "=", value: id})
+		case '.':
+			p.pos++
+			var class = p.ident()
+			if class == "" {
+				return c, fmt.Errorf("expected class name at position %d", p.pos)
+			}
+			c.attrs = append(c.attrs, attributeSelector{name: "class", op: "~=", value: class})
+		case '[':
+			var a attributeSelector
+			if a, err = p.attribute(); err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			var pc pseudoClass
+			if pc, err = p.pseudo(); err != nil {
+				return c, err
+			}
+			c.pseudos = append(c.pseudos, pc)
+		default:
+			if p.pos == start {
+				return c, fmt.Errorf("expected selector at position %d", p.pos)
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, fmt.Errorf("expected selector at end")
+	}
+	return c, nil
+}
+
+// attribute parses an attribute selector, like [name='value']
+func (p *selectorParser) attribute() (a attributeSelector, err error) {
+	p.pos++ // skip [
+	p.whitespace()
+	if a.name = strings.ToLower(p.ident()); a.name == "" {
+		return a, fmt.Errorf("expected attribute name at position %d", p.pos)
+	}
+	p.whitespace()
+
+	if p.pos < len(p.s) && p.s[p.pos] != ']' {
+		for _, op := range []string{"=", "~=", "^=", "$=", "*=", "|="} {
+			if strings.HasPrefix(p.s[p.pos:], op) {
+				a.op = op
+			}
+		}
+		if a.op == "" {
+			return a, fmt.Errorf("unsupported attribute operator at position %d", p.pos)
+		}
+		p.pos += len(a.op)
+		p.whitespace()
+		if a.value, err = p.value(); err != nil {
+			return a, err
+		}
+		p.whitespace()
+	}
+
+	if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+		return a, fmt.Errorf("expected ']' at position %d", p.pos)
+	}
+	p.pos++
+	return a, nil
+}
+
+// pseudo parses a pseudo-class, like :first-child or :nth-child(2)
+func (p *selectorParser) pseudo() (pc pseudoClass, err error) {
+	p.pos++ // skip :
+	switch pc.name = strings.ToLower(p.ident()); pc.name {
+	case "first-child", "last-child", "checked", "disabled":
+		return pc, nil
+	case "nth-child":
+		var end = strings.IndexByte(p.s[p.pos:], ')')
+		if !strings.HasPrefix(p.s[p.pos:], "(") || end < 0 {
+			return pc, fmt.Errorf("expected (n) after :nth-child")
+		}
+		if pc.n, err = strconv.Atoi(strings.TrimSpace(p.s[p.pos+1 : p.pos+end])); err != nil || pc.n < 1 {
+			return pc, fmt.Errorf(":nth-child only supports positive integers")
+		}
+		p.pos += end + 1
+		return pc, nil
+	}
+	return pc, fmt.Errorf("unsupported pseudo-class ':%s'", pc.name)
+}
+
+// value parses a quoted string or an identifier
+func (p *selectorParser) value() (string, error) {
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		var quote = p.s[p.pos]
+		var end = strings.IndexByte(p.s[p.pos+1:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string at position %d", p.pos)
+		}
+		var v = p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return v, nil
+	}
+	return p.ident(), nil
+}
+
+// ident parses an identifier (without support for escapes)
+func (p *selectorParser) ident() string {
+	var start = p.pos
+	for p.pos < len(p.s) {
+		var c = p.s[p.pos]
+		if c == '-' || c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			p.pos++
+		} else {
+			break
+		}
+	}
+	return p.s[start:p.pos]
+}
@@ -0,0 +1,70 @@
+package dom
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+	"testing"
+)
+
+const page = `<!doctype html>
+<html><body>
+  <div id="main" class="content wide">
+    <h1 lang="en-US">Title</h1>
+    <ul>
+      <li class="item first">One</li>
+      <li class="item" data-id="2">Two</li>
+      <li class="item last"><a href="/three">Three</a></li>
+    </ul>
+    <p>Para</p>
+  </div>
+  <form id="login"><input type="checkbox" name="remember" checked></form>
+</body></html>`
+
+func texts(t *testing.T, selector string) string {
+	t.Helper()
+	var doc, _ = html.Parse(strings.NewReader(page))
+	var sel, err = CompileSelector(selector)
+	if err != nil {
+		t.Fatalf("failed to compile %s: %v", selector, err)
+	}
+	var result []string
+	for _, n := range sel.Select(doc) {
+		result = append(result, Text(n))
+	}
+	return strings.Join(result, "|")
+}
+
+func TestSelector(t *testing.T) {
+	var cases = map[string]string{
+		"li":                               "One|Two|Three",
+		"LI.item.first":                    "One",
+		"#main > h1":                       "Title",
+		"body > li":                        "",
+		"div li a":                         "Three",
+		"li[data-id]":                      "Two",
+		"li[data-id='2'], h1":              "Title|Two",
+		`[class~=last]`:                    "Three",
+		`a[href^="/th"]`:                   "Three",
+		`a[href$=ree]`:                     "Three",
+		`div[class*="ten"] > p`:            "Para",
+		`h1[lang|=en]`:                     "Title",
+		"li:first-child":                   "One",
+		"li:last-child":                    "Three",
+		"li:nth-child(2)":                  "Two",
+		"h1 + ul > li ~ li":                "Two|Three",
+		"ul ~ p":                           "Para",
+		"input:checked":                    "",
+		"form:first-child, *:nth-child(9)": "",
+	}
+	for selector, expected := range cases {
+		assert(t, texts(t, selector) == expected, "%s: expected %q but got %q", selector, expected, texts(t, selector))
+	}
+
+	var doc, _ = html.Parse(strings.NewReader(page))
+	assert(t, len(mustCompile("input:checked").Select(doc)) == 1, "must match checked input")
+
+	for _, invalid := range []string{"", "li,", "li >", "[", "[a", "[a!=b]", "li:hover", "li:nth-child(x)", "a[b='c]", "#", "li $"} {
+		var _, err = CompileSelector(invalid)
+		assert(t, err != nil, "must fail to compile %q", invalid)
+	}
+}
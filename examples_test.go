@@ -8,7 +8,9 @@ import (
 var t = (TestingT)(nil)
 
 var WithExecFn = func() ExecFn {
-	return func(*http.Request) (*http.Response, error) { return nil, nil }
+	return func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}
 }
 
 func ExampleAssertion_customAssertion() {
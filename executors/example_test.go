@@ -41,3 +41,15 @@ func Example_customClient() {
 		HaveCookie("a"),
 	)
 }
+
+func Example_stub() {
+	var stub = NewStub(
+		Route{Method: http.MethodGet, Pattern: "/users/*", Body: `{"name": "john"}`},
+	)
+
+	ExecFn(stub.Do).MakeRequest(
+		Get("https://example.com/users/1"),
+	).ExpectIt(t,
+		ToHaveStatus(http.StatusOK),
+	)
+}
@@ -57,11 +57,17 @@ func WithNoRedirect() func(*http.Client) {
 // WithHandler wraps the given http.Handler and returns an ExecFn that invokes
 // the handler on request and return the response. This ExecFn doesn't need to make network round-trip
 // and can be used to implement unit tests for http endpoints in your application.
+//
+// Note that the handler is invoked directly and so there's no support for cookie jars, redirects etc.
+// Use WithClient(WithHandlerTransport(handler)) if you need those.
 func WithHandler(handler http.Handler) httpx.ExecFn {
 	return func(request *http.Request) (*http.Response, error) {
 		var recorder = httptest.NewRecorder()
 		handler.ServeHTTP(recorder, request)
-		return recorder.Result(), nil
+
+		var response = recorder.Result()
+		response.Request = request
+		return response, nil
 	}
 }
 
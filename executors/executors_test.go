@@ -1,10 +1,15 @@
 package executors_test
 
 import (
+	"errors"
+	"io"
 	. "go.riyazali.net/httpx/executors"
+	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -16,6 +21,14 @@ func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
 	}
 }
 
+func require(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+		t.FailNow()
+	}
+}
+
 func TestCustomClient(t *testing.T) {
 	var jar, _ = cookiejar.New(&cookiejar.Options{})
 
@@ -53,3 +66,183 @@ func TestWithHandler(t *testing.T) {
 	_, _ = WithHandlerFn(handler)(&http.Request{})
 	assert(t, called, "handler must be invoked")
 }
+
+func TestWithHandlerTransport(t *testing.T) {
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/login", func(writer http.ResponseWriter, request *http.Request) {
+		http.SetCookie(writer, &http.Cookie{Name: "session", Value: "1"})
+		http.Redirect(writer, request, "/home", http.StatusFound)
+	})
+	mux.HandleFunc("/home", func(writer http.ResponseWriter, request *http.Request) {
+		if _, err := request.Cookie("session"); err != nil {
+			writer.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	mux.HandleFunc("/slow", func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("test")
+	})
+
+	t.Run("should follow redirects and use cookie jar", func(t *testing.T) {
+		var jar, _ = cookiejar.New(&cookiejar.Options{})
+		var request, _ = http.NewRequest(http.MethodGet, "http://example.com/login", nil)
+
+		var response, err = WithClient(WithHandlerTransport(mux), WithCookieJar(jar))(request)
+		assert(t, err == nil, "must not return error")
+		assert(t, response.StatusCode == http.StatusOK, "must follow redirect and send cookies")
+		assert(t, response.Request.URL.Path == "/home", "must set final request on response")
+	})
+
+	t.Run("should not follow redirects if disabled", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "http://example.com/login", nil)
+
+		var response, err = WithClient(WithHandlerTransport(mux), WithNoRedirect())(request)
+		assert(t, err == nil, "must not return error")
+		assert(t, response.StatusCode == http.StatusFound, "must not follow redirect")
+	})
+
+	t.Run("should honour client timeout", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "http://example.com/slow", nil)
+
+		var _, err = WithClient(WithHandlerTransport(mux), WithTimeout(10*time.Millisecond))(request)
+		assert(t, err != nil, "must return error if handler times out")
+	})
+
+	t.Run("should stop abandoned handler and close body after it returns", func(t *testing.T) {
+		var writeErr = make(chan error, 1)
+		var blocking = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			<-request.Context().Done()
+			_, _ = ioutil.ReadAll(request.Body) // must not race with the transport closing the body
+			var _, err = writer.Write([]byte("too late"))
+			writeErr <- err
+		})
+
+		var body = &closeRecorder{Reader: strings.NewReader("hello")}
+		var request, _ = http.NewRequest(http.MethodPost, "http://example.com/", body)
+		var _, err = WithClient(WithHandlerTransport(blocking), WithTimeout(10*time.Millisecond))(request)
+		require(t, err != nil, "must return error if handler times out")
+
+		select {
+		case err = <-writeErr:
+			assert(t, err != nil, "writes must fail once the request is abandoned")
+		case <-time.After(time.Second):
+			t.Fatalf("handler must be signalled to stop")
+		}
+		assert(t, body.closed(), "must close body once handler returns")
+	})
+
+	t.Run("should return error if handler panics", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "http://example.com/panic", nil)
+
+		var _, err = WithClient(WithHandlerTransport(mux))(request)
+		assert(t, err != nil, "must return error if handler panics")
+	})
+}
+
+func TestWithServer(t *testing.T) {
+	var handler http.HandlerFunc = func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Proto", request.Proto)
+		if request.TLS != nil {
+			writer.Header().Set("X-TLS", "true")
+		}
+	}
+
+	t.Run("should send request to server", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "/versions", nil)
+		var response, err = WithServer(t, handler)(request)
+		assert(t, err == nil, "must not return error: %v", err)
+		assert(t, response.Header.Get("X-Proto") == "HTTP/1.1", "must use HTTP/1.1")
+		assert(t, response.Header.Get("X-TLS") == "", "must not use TLS")
+	})
+
+	t.Run("should send request to tls server", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "http://example.com/versions", nil)
+		var response, err = WithServer(t, handler, WithTLS())(request)
+		assert(t, err == nil, "must not return error: %v", err)
+		assert(t, response.Header.Get("X-TLS") == "true", "must use TLS")
+	})
+
+	t.Run("should send request to http/2 server", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "/versions", nil)
+		var response, err = WithServer(t, handler, WithHTTP2())(request)
+		assert(t, err == nil, "must not return error: %v", err)
+		assert(t, response.Header.Get("X-Proto") == "HTTP/2.0", "must use HTTP/2")
+	})
+}
+
+func TestStub(t *testing.T) {
+	var stub = NewStub(
+		Route{Method: http.MethodGet, Pattern: "/users/*", Body: `{"name": "john"}`},
+		Route{Pattern: "https://example.com/fail", Err: errors.New("test")},
+	).On(Route{Method: http.MethodPost, Pattern: "/users", Status: http.StatusCreated, Header: http.Header{"Location": {"/users/1"}}})
+
+	t.Run("should return canned response", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "https://example.com/users/1", nil)
+		var response, err = stub.Do(request)
+		require(t, err == nil, "must not return error: %v", err)
+
+		var body, _ = ioutil.ReadAll(response.Body)
+		assert(t, response.StatusCode == http.StatusOK, "must default to status ok")
+		assert(t, string(body) == `{"name": "john"}`, "must return route's body")
+		assert(t, response.Request == request, "must set request on response")
+	})
+
+	t.Run("should return canned headers", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodPost, "/users", strings.NewReader("{}"))
+		var response, err = stub.Do(request)
+		require(t, err == nil, "must not return error: %v", err)
+		assert(t, response.StatusCode == http.StatusCreated, "must return route's status")
+		assert(t, response.Header.Get("Location") == "/users/1", "must return route's headers")
+	})
+
+	t.Run("should return canned error", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodGet, "https://example.com/fail", nil)
+		var _, err = stub.Do(request)
+		assert(t, err != nil && err.Error() == "test", "must return route's error")
+	})
+
+	t.Run("should return error if no route matches", func(t *testing.T) {
+		var request, _ = http.NewRequest(http.MethodDelete, "/users/1", nil)
+		var _, err = stub.Do(request)
+		assert(t, err != nil, "must return error")
+	})
+
+	t.Run("should record requests", func(t *testing.T) {
+		var requests = stub.Requests()
+		require(t, len(requests) == 4, "must record all requests")
+
+		var body, _ = ioutil.ReadAll(requests[1].Body)
+		assert(t, requests[1].Method == http.MethodPost, "must record requests in order")
+		assert(t, string(body) == "{}", "must record request body")
+	})
+}
+
+// closeRecorder is a request body that records whether it has been closed
+type closeRecorder struct {
+	io.Reader
+	mu     sync.Mutex
+	closes int
+}
+
+func (c *closeRecorder) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closes++
+	return nil
+}
+
+func (c *closeRecorder) closed() bool {
+	// the body is closed right after the handler returns; give it a moment
+	for i := 0; i < 100; i++ {
+		c.mu.Lock()
+		var closed = c.closes > 0
+		c.mu.Unlock()
+		if closed {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
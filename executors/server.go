@@ -0,0 +1,66 @@
+package executors
+
+import (
+	"crypto/tls"
+	"go.riyazali.net/httpx"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// CleanupT extends httpx.TestingT with the ability to register cleanup functions.
+// It is satisfied by *testing.T and *testing.B.
+type CleanupT interface {
+	httpx.TestingT
+	Cleanup(func())
+}
+
+// WithServer starts an httptest.Server around the given http.Handler and returns an ExecFn
+// that sends requests to it over a real network socket. The server is shut down when the test completes.
+//
+// Request URLs are rewritten to point to the server, so you can continue to use your
+// regular URLs (even relative ones, like "/versions") in your test cases. Use opts to customise
+// the server, like enabling TLS or HTTP/2 using WithTLS() and WithHTTP2().
+//
+// This sits between WithHandler(...) and WithClient(...) in fidelity, and is useful for testing behaviours
+// that are only observable over a socket, like Content-Length, chunked encoding, connection reuse and more.
+func WithServer(t CleanupT, handler http.Handler, opts ...func(*httptest.Server)) httpx.ExecFn {
+	t.Helper()
+
+	var server = httptest.NewUnstartedServer(handler)
+	for _, fn := range opts {
+		fn(server)
+	}
+
+	if server.TLS != nil {
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+
+	var target, _ = url.Parse(server.URL)
+	var client = server.Client() // configured to trust the server's certificate
+	return func(request *http.Request) (*http.Response, error) {
+		var r = request.Clone(request.Context())
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		return client.Do(r)
+	}
+}
+
+// WithTLS configures the server to use TLS.
+func WithTLS() func(*httptest.Server) {
+	return func(server *httptest.Server) {
+		if server.TLS == nil {
+			server.TLS = &tls.Config{}
+		}
+	}
+}
+
+// WithHTTP2 configures the server to use TLS and enables HTTP/2 on it.
+func WithHTTP2() func(*httptest.Server) {
+	return func(server *httptest.Server) {
+		WithTLS()(server)
+		server.EnableHTTP2 = true
+	}
+}
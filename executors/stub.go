@@ -0,0 +1,136 @@
+package executors
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Route defines an entry in a Stub's routing table.
+type Route struct {
+	// Method to match. An empty value matches any method.
+	Method string
+
+	// Pattern matched against the request url using path.Match(...). If the pattern contains a scheme
+	// (like "https://example.com/users/*") it is matched against the url's scheme, host and path,
+	// otherwise it is matched against the path alone.
+	Pattern string
+
+	// Status, Header and Body of the response returned when the route matches.
+	// Status defaults to http.StatusOK.
+	Status int
+	Header http.Header
+	Body   string
+
+	// Err, if set, is returned instead of the response.
+	Err error
+}
+
+// Stub is an in-memory executor that serves canned responses from a routing table,
+// and records all the requests it receives. Use its Do method as an ExecFn.
+//
+//  var stub = NewStub(Route{Method: http.MethodGet, Pattern: "/users/*", Body: `{"name": "john"}`})
+//  client := api.NewClient(stub.Do) // code under test accepts an httpx.ExecFn
+//  ...
+//  stub.Requests() // requests received by the stub
+type Stub struct {
+	mu       sync.Mutex
+	routes   []Route
+	requests []*http.Request
+}
+
+// NewStub returns a new Stub with the given routes. Routes are matched in order.
+func NewStub(routes ...Route) *Stub {
+	return &Stub{routes: routes}
+}
+
+// On adds a new route to the stub's routing table.
+func (s *Stub) On(route Route) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route)
+	return s
+}
+
+// Do finds the first matching route and returns its response (or error).
+// Do returns an error if no route matches the request.
+func (s *Stub) Do(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, fmt.Errorf("executors: stub: failed to read request body: %v", err)
+		}
+		_ = request.Body.Close()
+	}
+
+	// record a copy of the request with a re-readable body
+	var recorded = request.Clone(request.Context())
+	recorded.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recorded)
+	var routes = s.routes
+	s.mu.Unlock()
+
+	for _, route := range routes {
+		if route.match(request) {
+			if route.Err != nil {
+				return nil, route.Err
+			}
+			return route.response(request), nil
+		}
+	}
+	return nil, fmt.Errorf("executors: stub: no route matches %s %s", request.Method, request.URL)
+}
+
+// Requests returns all the requests received by the stub, in order.
+// Each request's body can be read again.
+func (s *Stub) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+func (route Route) match(request *http.Request) bool {
+	if route.Method != "" && !strings.EqualFold(route.Method, request.Method) {
+		return false
+	}
+
+	var u = request.URL.Path
+	if strings.Contains(route.Pattern, "://") {
+		u = request.URL.Scheme + "://" + request.URL.Host + request.URL.Path
+	}
+	var ok, _ = path.Match(route.Pattern, u)
+	return ok
+}
+
+func (route Route) response(request *http.Request) *http.Response {
+	var status = route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	var header = route.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(route.Body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(route.Body)),
+		ContentLength: int64(len(route.Body)),
+		Request:       request,
+	}
+}
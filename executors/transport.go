@@ -0,0 +1,115 @@
+package executors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// HandlerTransport is an http.RoundTripper that routes requests to an in-memory http.Handler.
+//
+// Unlike WithHandler(...), requests made using a HandlerTransport go through an actual http.Client
+// and so gets all the goodies the client provides, like cookie jars, following redirects,
+// CheckRedirect and timeouts, without ever opening a network socket.
+type HandlerTransport struct {
+	Handler http.Handler
+}
+
+// RoundTrip implements http.RoundTripper. It converts the outgoing client request into a server request,
+// invokes the handler with it and returns the recorded response.
+func (h *HandlerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// RoundTrip must not modify the request, so we work on a shallow copy
+	// and fill in fields a handler would normally expect from a server request
+	var server = request.Clone(request.Context())
+	if server.Body == nil {
+		server.Body = http.NoBody
+	}
+	if server.Host == "" && server.URL != nil {
+		server.Host = server.URL.Host
+	}
+	if server.URL != nil {
+		server.RequestURI = server.URL.RequestURI()
+	}
+	server.RemoteAddr = "192.0.2.1:1234" // same as httptest.NewRequest(...)
+
+	// invoke the handler in a separate goroutine so that we can honour
+	// request cancellation (and therefore client timeouts) even if the handler doesn't.
+	// The goroutine owns the request body until the handler returns, and closes it then
+	// (RoundTrip must always close the body, but is allowed to do so after it has returned).
+	var recorder = httptest.NewRecorder()
+	var writer = &abortableWriter{ctx: server.Context(), recorder: recorder}
+	var done = make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if request.Body != nil {
+				_ = request.Body.Close()
+			}
+			done <- recover()
+		}()
+		h.Handler.ServeHTTP(writer, server)
+	}()
+
+	select {
+	case <-request.Context().Done():
+		// the handler sees the cancellation through its request's context, and
+		// the writer fails any writes it makes from then on, so that it stops as soon as possible
+		return nil, request.Context().Err()
+	case v := <-done:
+		if v != nil {
+			return nil, fmt.Errorf("executors: handler panicked: %v", v)
+		}
+	}
+
+	var response = recorder.Result()
+	response.Request = request
+	return response, nil
+}
+
+// WithHandlerTransport sets a HandlerTransport wrapping the given http.Handler on the http.Client.
+// Use this with WithClient(...) to test a local handler using a real http.Client.
+//
+//  WithClient(WithHandlerTransport(handler), WithCookieJar(jar), WithNoRedirect())
+func WithHandlerTransport(handler http.Handler) func(*http.Client) {
+	return func(client *http.Client) {
+		client.Transport = &HandlerTransport{Handler: handler}
+	}
+}
+
+// abortableWriter is an http.ResponseWriter that stops accepting writes once the request's context is done,
+// so that an abandoned handler neither writes into a response no one will read nor runs for longer than it has to.
+type abortableWriter struct {
+	mu       sync.Mutex
+	ctx      context.Context // context of the server request
+	recorder *httptest.ResponseRecorder
+}
+
+// Header returns the recorder's header map. Once the request is abandoned, the recorder is never read again,
+// and so it's fine for an abandoned handler to keep modifying it.
+func (w *abortableWriter) Header() http.Header { return w.recorder.Header() }
+
+func (w *abortableWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.recorder.Write(p)
+}
+
+func (w *abortableWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() == nil {
+		w.recorder.WriteHeader(status)
+	}
+}
+
+func (w *abortableWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() == nil {
+		w.recorder.Flush()
+	}
+}
@@ -0,0 +1,118 @@
+package faults
+
+import (
+	"go.riyazali.net/httpx"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TruncateBody returns a Fault that truncates the response body after n bytes.
+// Reading past the truncated body returns io.ErrUnexpectedEOF, just like a prematurely closed connection would.
+// Bodies no longer than n bytes are left untouched.
+func TruncateBody(n int64) Fault {
+	return body(func(response *http.Response) io.Reader {
+		return &truncated{r: response.Body, n: n}
+	})
+}
+
+// CorruptBody returns a Fault that corrupts every n-th byte of the response body by flipping all of its bits.
+func CorruptBody(n int) Fault {
+	return body(func(response *http.Response) io.Reader {
+		return &corrupted{r: response.Body, n: n}
+	})
+}
+
+// SlowBody returns a Fault that slows down reads from the response body,
+// returning at most chunk bytes on every read after waiting for the given interval. A chunk smaller than 1 is treated as 1.
+func SlowBody(chunk int, interval time.Duration) Fault {
+	if chunk < 1 {
+		chunk = 1
+	}
+	return body(func(response *http.Response) io.Reader {
+		return &slow{r: response.Body, request: response.Request, chunk: chunk, interval: interval}
+	})
+}
+
+// body returns a Fault that wraps the response body using the given function
+func body(wrap func(*http.Response) io.Reader) Fault {
+	return func(next httpx.ExecFn) httpx.ExecFn {
+		return func(request *http.Request) (*http.Response, error) {
+			var response, err = next(request)
+			if err != nil || response == nil || response.Body == nil {
+				return response, err
+			}
+			if response.Request == nil {
+				response.Request = request
+			}
+			response.Body = readCloser{Reader: wrap(response), Closer: response.Body}
+			response.ContentLength = -1 // we no longer know the exact length of the body
+			return response, nil
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type truncated struct {
+	r io.Reader
+	n int64 // remaining bytes
+}
+
+func (t *truncated) Read(p []byte) (int, error) {
+	if t.n <= 0 {
+		// only report truncation if the body actually had more to give
+		var probe [1]byte
+		for {
+			var n, err = t.r.Read(probe[:])
+			if n > 0 {
+				return 0, io.ErrUnexpectedEOF
+			} else if err != nil {
+				return 0, err
+			}
+		}
+	}
+	if int64(len(p)) > t.n {
+		p = p[:t.n]
+	}
+	var n, err = t.r.Read(p)
+	t.n -= int64(n)
+	return n, err
+}
+
+type corrupted struct {
+	r   io.Reader
+	n   int
+	off int // number of bytes read so far
+}
+
+func (c *corrupted) Read(p []byte) (int, error) {
+	var n, err = c.r.Read(p)
+	for i := 0; i < n; i++ {
+		if c.n > 0 && (c.off+i+1)%c.n == 0 {
+			p[i] = ^p[i]
+		}
+	}
+	c.off += n
+	return n, err
+}
+
+type slow struct {
+	r        io.Reader
+	request  *http.Request
+	chunk    int
+	interval time.Duration
+}
+
+func (s *slow) Read(p []byte) (int, error) {
+	if err := sleep(s.request, s.interval); err != nil {
+		return 0, err
+	}
+	if len(p) > s.chunk {
+		p = p[:s.chunk]
+	}
+	return s.r.Read(p)
+}
@@ -0,0 +1,150 @@
+// Package faults provides an httpx.ExecFn decorator that injects faults into request execution.
+//
+// Use it to verify the retry and timeout logic of your handlers and clients, driven through the same
+// MakeRequest(...) / ExpectIt(...) api. Faults can be injected on every call, with a probability
+// (using a seedable random number generator, for reproducibility) or on a deterministic schedule.
+//
+//  var rng = faults.NewRand(42)
+//  faults.Inject(WithHandler(handler),
+//    faults.When(faults.Probability(rng, 0.1), faults.ConnectionReset()),
+//    faults.When(faults.OnCalls(2, 3), faults.Latency(500*time.Millisecond)),
+//  ).MakeRequest(...)
+package faults // import "go.riyazali.net/httpx/faults"
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault defines a function that decorates an httpx.ExecFn to inject some fault into the execution.
+type Fault func(httpx.ExecFn) httpx.ExecFn
+
+// Inject decorates the given ExecFn with the given faults. Faults are applied in order,
+// ie. the first fault is the outermost.
+func Inject(fn httpx.ExecFn, faults ...Fault) httpx.ExecFn {
+	for i := len(faults) - 1; i >= 0; i-- {
+		fn = faults[i](fn)
+	}
+	return fn
+}
+
+// When returns a Fault that only injects the given fault when the trigger fires.
+// The trigger is consulted exactly once per request.
+func When(trigger Trigger, fault Fault) Fault {
+	return func(next httpx.ExecFn) httpx.ExecFn {
+		var faulty = fault(next)
+		return func(request *http.Request) (*http.Response, error) {
+			if trigger() {
+				return faulty(request)
+			}
+			return next(request)
+		}
+	}
+}
+
+// Latency returns a Fault that delays the execution of request by the given duration.
+// If the request is cancelled while waiting, the context's error is returned.
+func Latency(d time.Duration) Fault {
+	return func(next httpx.ExecFn) httpx.ExecFn {
+		return func(request *http.Request) (*http.Response, error) {
+			if err := sleep(request, d); err != nil {
+				return nil, urlError(request, err)
+			}
+			return next(request)
+		}
+	}
+}
+
+// ConnectionReset returns a Fault that fails the request with a "connection reset by peer" error.
+func ConnectionReset() Fault {
+	return func(httpx.ExecFn) httpx.ExecFn {
+		return func(request *http.Request) (*http.Response, error) {
+			var err = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+			return nil, urlError(request, err)
+		}
+	}
+}
+
+// Timeout returns a Fault that waits for the given duration (or until the request is cancelled)
+// and then fails the request with a timeout error, like the one returned by http.Client on timeouts.
+// The returned error implements net.Error and its Timeout() method returns true.
+func Timeout(d time.Duration) Fault {
+	return func(httpx.ExecFn) httpx.ExecFn {
+		return func(request *http.Request) (*http.Response, error) {
+			if err := sleep(request, d); err != nil {
+				return nil, urlError(request, err)
+			}
+			return nil, urlError(request, timeoutError{})
+		}
+	}
+}
+
+// Status returns a Fault that short-circuits the request and returns
+// an empty response with the given status code instead.
+func Status(code int) Fault {
+	return func(httpx.ExecFn) httpx.ExecFn {
+		return func(request *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+				StatusCode: code,
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     make(http.Header),
+				Body:       http.NoBody,
+				Request:    request,
+			}, nil
+		}
+	}
+}
+
+// timeoutError is the error returned by Timeout(...) fault
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "faults: i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// urlError wraps the error in a *url.Error, just like http.Client does
+func urlError(request *http.Request, err error) error {
+	return &url.Error{Op: request.Method, URL: request.URL.String(), Err: err}
+}
+
+// sleep waits for the given duration or until the request is cancelled
+func sleep(request *http.Request, d time.Duration) error {
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-request.Context().Done():
+		return request.Context().Err()
+	}
+}
+
+// Rand is a random number generator that is safe for concurrent use.
+// Use NewRand(...) with a fixed seed to reproduce the same sequence of faults across runs.
+type Rand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRand returns a new Rand seeded with the given value.
+func NewRand(seed int64) *Rand {
+	return &Rand{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0)
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64()
+}
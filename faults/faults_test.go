@@ -0,0 +1,175 @@
+package faults_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/faults"
+	. "go.riyazali.net/httpx/helpers"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+func require(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+		t.FailNow()
+	}
+}
+
+var handler = WithHandlerFn(func(writer http.ResponseWriter, request *http.Request) {
+	_, _ = io.WriteString(writer, "hello world")
+})
+
+func do(fn ExecFn) (*http.Response, error) {
+	var request, _ = http.NewRequest(http.MethodGet, "https://example.com", nil)
+	return fn(request)
+}
+
+func TestInject(t *testing.T) {
+	t.Run("should return status", func(t *testing.T) {
+		faults.Inject(handler, faults.Status(http.StatusServiceUnavailable)).
+			MakeRequest(Get("https://example.com")).
+			ExpectIt(t, ToHaveStatus(http.StatusServiceUnavailable))
+	})
+
+	t.Run("should reset connection", func(t *testing.T) {
+		var _, err = do(faults.Inject(handler, faults.ConnectionReset()))
+		assert(t, errors.Is(err, syscall.ECONNRESET), "must return connection reset error")
+	})
+
+	t.Run("should time out", func(t *testing.T) {
+		var _, err = do(faults.Inject(handler, faults.Timeout(time.Millisecond)))
+		var ne net.Error
+		assert(t, errors.As(err, &ne) && ne.Timeout(), "must return timeout error")
+	})
+
+	t.Run("should add latency", func(t *testing.T) {
+		var start = time.Now()
+		faults.Inject(handler, faults.Latency(20*time.Millisecond)).
+			MakeRequest(Get("https://example.com")).
+			ExpectIt(t, ToHaveStatus(http.StatusOK))
+		assert(t, time.Since(start) >= 20*time.Millisecond, "must delay the request")
+	})
+
+	t.Run("should honour request cancellation", func(t *testing.T) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		var request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+		var _, err = faults.Inject(handler, faults.Latency(time.Minute))(request)
+		assert(t, errors.Is(err, context.DeadlineExceeded), "must return context's error")
+	})
+
+	t.Run("should truncate body", func(t *testing.T) {
+		var response, err = do(faults.Inject(handler, faults.TruncateBody(5)))
+		require(t, err == nil, "must not return error")
+
+		var body, rerr = ioutil.ReadAll(response.Body)
+		assert(t, string(body) == "hello", "must truncate body")
+		assert(t, rerr == io.ErrUnexpectedEOF, "must return unexpected eof")
+	})
+
+	t.Run("should not truncate shorter body", func(t *testing.T) {
+		for _, n := range []int64{11, 20} {
+			var response, err = do(faults.Inject(handler, faults.TruncateBody(n)))
+			require(t, err == nil, "must not return error")
+
+			var body, rerr = ioutil.ReadAll(response.Body)
+			assert(t, string(body) == "hello world", "must return whole body")
+			assert(t, rerr == nil, "must not return error for body of %d bytes: %v", n, rerr)
+		}
+	})
+
+	t.Run("should corrupt body", func(t *testing.T) {
+		faults.Inject(handler, faults.CorruptBody(2)).
+			MakeRequest(Get("https://example.com")).
+			ExpectIt(t, BodyBytes(func(body []byte) error {
+				return Multiple(
+					AssertThat(len(body) == 11, "must not change body length"),
+					AssertThat(body[0] == 'h' && body[1] == ^byte('e'), "must corrupt every 2nd byte"),
+				)
+			}))
+	})
+
+	t.Run("should slow down body", func(t *testing.T) {
+		var start = time.Now()
+		faults.Inject(handler, faults.SlowBody(4, 5*time.Millisecond)).
+			MakeRequest(Get("https://example.com")).
+			ExpectIt(t, BodyBytes(func(body []byte) error {
+				return AssertThat(bytes.Equal(body, []byte("hello world")), "must not alter body")
+			}))
+		assert(t, time.Since(start) >= 15*time.Millisecond, "must slow down reads")
+	})
+
+	t.Run("should read at least a byte at a time", func(t *testing.T) {
+		for _, chunk := range []int{0, -1} {
+			faults.Inject(handler, faults.SlowBody(chunk, 0)).
+				MakeRequest(Get("https://example.com")).
+				ExpectIt(t, BodyBytes(func(body []byte) error {
+					return AssertThat(bytes.Equal(body, []byte("hello world")), "must read body with chunk %d", chunk)
+				}))
+		}
+	})
+}
+
+func TestTriggers(t *testing.T) {
+	var count = func(n int, trigger faults.Trigger) (fired []int) {
+		var fn = faults.Inject(handler, faults.When(trigger, faults.Status(http.StatusBadGateway)))
+		for i := 1; i <= n; i++ {
+			if response, _ := do(fn); response.StatusCode == http.StatusBadGateway {
+				fired = append(fired, i)
+			}
+		}
+		return fired
+	}
+
+	t.Run("on calls", func(t *testing.T) {
+		var fired = count(5, faults.OnCalls(2, 4))
+		assert(t, len(fired) == 2 && fired[0] == 2 && fired[1] == 4, "must fire on given calls: %v", fired)
+	})
+
+	t.Run("every", func(t *testing.T) {
+		var fired = count(6, faults.Every(3))
+		assert(t, len(fired) == 2 && fired[0] == 3 && fired[1] == 6, "must fire on every n-th call: %v", fired)
+	})
+
+	t.Run("after", func(t *testing.T) {
+		var fired = count(4, faults.After(2))
+		assert(t, len(fired) == 2 && fired[0] == 3, "must fire after first n calls: %v", fired)
+	})
+
+	t.Run("always", func(t *testing.T) {
+		assert(t, len(count(3, faults.Always())) == 3, "must always fire")
+	})
+
+	t.Run("probability is reproducible", func(t *testing.T) {
+		var a = count(100, faults.Probability(faults.NewRand(42), 0.5))
+		var b = count(100, faults.Probability(faults.NewRand(42), 0.5))
+		assert(t, len(a) == len(b) && len(a) > 0 && len(a) < 100, "must fire with given probability")
+		for i := range a {
+			assert(t, a[i] == b[i], "must fire on same calls with same seed")
+		}
+	})
+
+	t.Run("probability without rand", func(t *testing.T) {
+		assert(t, len(count(10, faults.Probability(nil, 1))) == 10, "must use a default rand")
+		assert(t, len(count(10, faults.Probability(nil, 0))) == 0, "must use a default rand")
+	})
+}
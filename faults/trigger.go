@@ -0,0 +1,58 @@
+package faults
+
+import (
+	"sync"
+	"time"
+)
+
+// Trigger defines a function that decides whether a fault should be injected for a request or not.
+// Triggers are consulted once per request, and are safe for concurrent use.
+type Trigger func() bool
+
+// Always returns a Trigger that always fires.
+func Always() Trigger {
+	return func() bool { return true }
+}
+
+// Probability returns a Trigger that fires with probability p (between 0 and 1), using the given Rand.
+// If rng is nil, a Rand seeded with the current time is used, and so faults are not reproducible across runs.
+func Probability(rng *Rand, p float64) Trigger {
+	if rng == nil {
+		rng = NewRand(time.Now().UnixNano())
+	}
+	return func() bool { return rng.Float64() < p }
+}
+
+// OnCalls returns a Trigger that fires only on the given calls (numbered from 1).
+func OnCalls(calls ...int) Trigger {
+	return counter(func(n int) bool {
+		for _, c := range calls {
+			if c == n {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Every returns a Trigger that fires on every n-th call.
+func Every(n int) Trigger {
+	return counter(func(c int) bool { return n > 0 && c%n == 0 })
+}
+
+// After returns a Trigger that fires on every call after the first n calls.
+func After(n int) Trigger {
+	return counter(func(c int) bool { return c > n })
+}
+
+// counter returns a Trigger that invokes fn with the (1-based) number of the current call
+func counter(fn func(int) bool) Trigger {
+	var mu sync.Mutex
+	var n = 0
+	return func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return fn(n)
+	}
+}
@@ -1,3 +1,5 @@
 module go.riyazali.net/httpx
 
-go 1.13
+go 1.18
+
+require golang.org/x/net v0.33.0
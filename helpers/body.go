@@ -23,3 +23,26 @@ func SerializeJson(obj interface{}) io.ReadCloser {
 	_ = json.NewEncoder(&buf).Encode(obj)
 	return ioutil.NopCloser(&buf)
 }
+
+// BufferBody reads the whole response body into memory and returns a function that resets response.Body
+// to a fresh reader over the buffered bytes. Use it in assertions that invoke other assertions, each of which
+// may want to read the body, by calling rewind before (and after) invoking each of them.
+//
+//    var rewind, err = BufferBody(response)
+//    for _, fn := range assertions {
+//        rewind()
+//        ... = fn(response)
+//    }
+//    rewind()
+func BufferBody(response *http.Response) (rewind func(), err error) {
+	var body []byte
+	if response.Body != nil {
+		body, err = ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+	}
+	rewind = func() {
+		response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	rewind()
+	return rewind, err
+}
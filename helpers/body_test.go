@@ -15,3 +15,15 @@ func TestSerializeJson(t *testing.T) {
 	var body, _ = ioutil.ReadAll(SerializeJson(map[string]string{"a": "1"}))
 	assert(t, bytes.Equal(body, []byte("{\"a\":\"1\"}\n")), "must return json representation of object")
 }
+
+func TestBufferBody(t *testing.T) {
+	var response = &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString("hello"))}
+	var rewind, err = BufferBody(response)
+	assert(t, err == nil, "must not return error")
+
+	for i := 0; i < 2; i++ {
+		var body, _ = ioutil.ReadAll(response.Body)
+		assert(t, string(body) == "hello", "must allow reading body again after rewind")
+		rewind()
+	}
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import "strings"
+
+// ParseList parses the given header values as comma-separated lists (as defined by RFC 7230) and returns
+// the combined list of elements. Whitespace around elements is trimmed, empty elements are dropped,
+// and commas inside quoted strings are not treated as separators.
+func ParseList(values ...string) (elements []string) {
+	for _, value := range values {
+		var quoted, escaped bool
+		var start = 0
+		for i := 0; i < len(value); i++ {
+			switch c := value[i]; {
+			case escaped:
+				escaped = false
+			case c == '\\' && quoted:
+				escaped = true
+			case c == '"':
+				quoted = !quoted
+			case c == ',' && !quoted:
+				if e := strings.TrimSpace(value[start:i]); e != "" {
+					elements = append(elements, e)
+				}
+				start = i + 1
+			}
+		}
+		if e := strings.TrimSpace(value[start:]); e != "" {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,12 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	var list = ParseList("no-cache, max-age=0", ` private="a, b" ,, `)
+	assert(t, reflect.DeepEqual(list, []string{"no-cache", "max-age=0", `private="a, b"`}), "must parse list: %q", list)
+	assert(t, ParseList() == nil, "must return nil for no values")
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LookupJsonPath looks up the value at the given path in a json document decoded into an interface{}
+// (ie. made of map[string]interface{}, []interface{} and primitive values).
+//
+// The path is a dot-separated list of object keys with optional array indices, like "data.users[0].name",
+// and may optionally start with "$" to denote the document root. An empty path (or "$") returns the document itself.
+func LookupJsonPath(doc interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc, nil
+	}
+
+	var current = doc
+	for _, part := range strings.Split(path, ".") {
+		// split "key[0][1]" into "key" and indices 0, 1
+		var key = part
+		var indices []string
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			key = part[:i]
+			indices = strings.Split(strings.TrimSuffix(part[i+1:], "]"), "][")
+		}
+
+		if key != "" {
+			var obj, ok = current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("path: cannot lookup key '%s' in non-object value", key)
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("path: key '%s' not found", key)
+			}
+		}
+
+		for _, index := range indices {
+			var i, err = strconv.Atoi(index)
+			if err != nil {
+				return nil, fmt.Errorf("path: invalid index '%s'", index)
+			}
+			var arr, ok = current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("path: cannot index non-array value")
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("path: index %d out of range (length %d)", i, len(arr))
+			}
+			current = arr[i]
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookupJsonPath(t *testing.T) {
+	var doc interface{}
+	_ = json.Unmarshal([]byte(`{"data": {"users": [{"name": "john"}, {"name": "jane", "tags": [["a"]]}]}}`), &doc)
+
+	var v, err = LookupJsonPath(doc, "$.data.users[1].name")
+	assert(t, err == nil && v == "jane", "must lookup nested value")
+
+	v, err = LookupJsonPath(doc, "data.users[1].tags[0][0]")
+	assert(t, err == nil && v == "a", "must lookup nested arrays")
+
+	v, err = LookupJsonPath(doc, "$")
+	assert(t, err == nil && v != nil, "must return document for root path")
+
+	_, err = LookupJsonPath(doc, "data.users[2]")
+	assert(t, err != nil, "must return error if index is out of range")
+
+	_, err = LookupJsonPath(doc, "data.groups")
+	assert(t, err != nil, "must return error if key is not found")
+
+	_, err = LookupJsonPath(doc, "data.users.name")
+	assert(t, err != nil, "must return error if looking up key in array")
+}
@@ -0,0 +1,135 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Link is a single link parsed from a Link header (see RFC 8288).
+type Link struct {
+	// URI is the (possibly relative) target of the link
+	URI string
+
+	// Params contains the link's parameters, with lower-cased names. As per the RFC,
+	// only the first occurrence of a parameter is kept.
+	Params map[string]string
+}
+
+// Rels returns the link's (lower-cased) relation types. A link can have multiple space-separated relation types.
+func (l Link) Rels() []string {
+	return strings.Fields(strings.ToLower(l.Params["rel"]))
+}
+
+// HasRel returns true if the link has the given relation type (compared case-insensitively).
+func (l Link) HasRel(rel string) bool {
+	for _, r := range l.Rels() {
+		if r == strings.ToLower(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLinks parses the given Link header values, returning an error if any of them is malformed, like,
+//
+//    links, err := ParseLinks(`<https://example.com/users?page=2>; rel="next", </users?page=5>; rel=last`)
+func ParseLinks(values ...string) (links []Link, err error) {
+	for _, value := range values {
+		var p = &linkParser{value: value}
+		for p.skip(" \t,"); p.pos < len(p.value); p.skip(" \t,") {
+			var link Link
+			if link, err = p.link(); err != nil {
+				return nil, fmt.Errorf("link: %v in '%s'", err, value)
+			}
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// linkParser is a simple recursive-descent parser for Link header values
+type linkParser struct {
+	value string
+	pos   int
+}
+
+func (p *linkParser) skip(chars string) {
+	for p.pos < len(p.value) && strings.IndexByte(chars, p.value[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// link parses a single link-value, ie. <uri> followed by zero or more ;-separated parameters
+func (p *linkParser) link() (Link, error) {
+	var link = Link{Params: make(map[string]string)}
+	if p.value[p.pos] != '<' {
+		return link, fmt.Errorf("expected '<' at position %d", p.pos)
+	}
+	var end = strings.IndexByte(p.value[p.pos:], '>')
+	if end < 0 {
+		return link, fmt.Errorf("unterminated uri reference")
+	}
+	link.URI = strings.TrimSpace(p.value[p.pos+1 : p.pos+end])
+	p.pos += end + 1
+
+	for {
+		if p.skip(" \t"); p.pos >= len(p.value) || p.value[p.pos] == ',' {
+			return link, nil
+		}
+		if p.value[p.pos] != ';' {
+			return link, fmt.Errorf("expected ';' or ',' at position %d", p.pos)
+		}
+		p.pos++
+		p.skip(" \t")
+
+		var name = strings.ToLower(p.token())
+		if name == "" {
+			return link, fmt.Errorf("expected parameter name at position %d", p.pos)
+		}
+
+		var value string
+		if p.skip(" \t"); p.pos < len(p.value) && p.value[p.pos] == '=' {
+			p.pos++
+			p.skip(" \t")
+			var err error
+			if value, err = p.parameterValue(); err != nil {
+				return link, err
+			}
+		}
+		if _, ok := link.Params[name]; !ok {
+			link.Params[name] = value
+		}
+	}
+}
+
+// token parses a token (any run of characters other than separators and whitespace)
+func (p *linkParser) token() string {
+	var start = p.pos
+	for p.pos < len(p.value) && strings.IndexByte(" \t;,=\"<>", p.value[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.value[start:p.pos]
+}
+
+// parameterValue parses a token or a quoted string
+func (p *linkParser) parameterValue() (string, error) {
+	if p.pos >= len(p.value) || p.value[p.pos] != '"' {
+		return p.token(), nil
+	}
+
+	var buf strings.Builder
+	for p.pos++; p.pos < len(p.value); p.pos++ {
+		switch c := p.value[p.pos]; c {
+		case '\\':
+			if p.pos++; p.pos < len(p.value) {
+				buf.WriteByte(p.value[p.pos])
+			}
+		case '"':
+			p.pos++
+			return buf.String(), nil
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted string")
+}
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	var links, err = ParseLinks(
+		`<https://example.com/users?page=2&sort=a,b>; rel="next prev"; title="a \"quoted\", title", </users?page=5>;REL=last;rel=first`,
+		`<//example.com/users?page=1> ; anchor="#x"`,
+	)
+	assert(t, err == nil, "must not return error: %v", err)
+	assert(t, len(links) == 3, "must parse all links: %v", links)
+	assert(t, links[0].URI == "https://example.com/users?page=2&sort=a,b", "must allow commas in uri")
+	assert(t, links[0].HasRel("next") && links[0].HasRel("PREV"), "must split relation types")
+	assert(t, links[0].Params["title"] == `a "quoted", title`, "must unquote values")
+	assert(t, links[1].HasRel("last") && !links[1].HasRel("first"), "must keep first occurrence of parameter")
+	assert(t, links[2].Params["anchor"] == "#x" && len(links[2].Rels()) == 0, "must parse parameters without rel")
+
+	for _, malformed := range []string{`https://example.com; rel=next`, `<https://example.com`, `<a>; rel="next`, `<a> rel=next`, `<a>; =next`} {
+		_, err = ParseLinks(malformed)
+		assert(t, err != nil, "must fail for malformed value %s", malformed)
+	}
+}
@@ -5,11 +5,12 @@
 package httpx // import "go.riyazali.net/httpx"
 
 import (
-	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 // ExecFn defines a function that can take an http.Request and return an http.Response (and optionally, an error).
@@ -36,47 +37,15 @@ type ExecFn func(*http.Request) (*http.Response, error)
 // The core library provides certain general purpose builders. See RequestBuilder and it's implementations
 // in builders package for more details and how you can create a custom builder.
 func (fn ExecFn) MakeRequest(factory RequestFactory, builders ...RequestBuilder) Assertable {
-	var err error
-
-	// build a new request and apply customisations
-	var request *http.Request
-	if request, err = factory(); err != nil {
-		return fail("httpx: failed to create request: %v", err)
-	}
-
-	for _, fn := range builders {
-		if err = fn(request); err != nil {
-			return fail("httpx: builder: %v", err)
-		}
-	}
-
-	// execute the request
-	var response *http.Response
-	if response, err = fn(request); err != nil {
-		return fail("httpx: failed to execute request: %v", err)
+	var result = fn.execute(factory, builders)
+	if result.Err != nil {
+		return fail(result)
 	}
 
 	// return an Assertable to run assertions on response
 	return func(t TestingT, assertions ...Assertion) {
 		t.Helper()
-		defer response.Body.Close() // make sure to close the original response body always
-
-		// prepare a bytes.Buffer that'd allow us to seek to start after every assertion
-		// so that we can have multiple assertions that could read response's body
-		var buf bytes.Buffer
-		if _, err := buf.ReadFrom(response.Body); err != nil {
-			t.Errorf("httpx: failed to read body into buffer: %v", err)
-			t.FailNow()
-		}
-		var reader = bytes.NewReader(buf.Bytes())
-		response.Body = ioutil.NopCloser(reader)
-
-		for _, fn := range assertions {
-			if err = fn(response); err != nil {
-				t.Errorf("httpx: assertion: %v", err)
-			}
-			_, _ = reader.Seek(0, io.SeekStart) // safe to ignore return values
-		}
+		deliver(t, result.evaluate(assertions))
 	}
 }
 
@@ -137,20 +106,58 @@ func Delete(url string) RequestFactory {
 	return Using(http.MethodDelete, url, nil)
 }
 
+// Options is a shorthand method to create a RequestFactory with http.MethodOptions
+func Options(url string) RequestFactory {
+	return Using(http.MethodOptions, url, nil)
+}
+
 // TestingT allows us to decouple our code from the actual testing.T type.
 // Most end user shouldn't care about it. It is marked as exported because it
 // appears as part of the exported function signature of httpx.
+//
+// A custom Assertable that wraps the TestingT it is given (say, to decorate failure messages) should
+// also implement Unwrap() TestingT, returning the wrapped value, so that Evaluate(...) still works through it.
 type TestingT interface {
 	Errorf(format string, args ...interface{})
 	FailNow()
 	Helper()
 }
 
-// fail returns a no-op Assertable that allows us to break out of MakeRequest(...) quicker.
-func fail(format string, args ...interface{}) Assertable {
+// execute builds a new request using the given factory and builders, and executes it using fn.
+// The response body is left unread, so that the caller can decide whether to buffer or stream it.
+// If the request cannot be built or executed, the returned Result has Err set (and the Request, if one was built).
+func (fn ExecFn) execute(factory RequestFactory, builders []RequestBuilder) *Result {
+	var err error
+	var result = &Result{}
+
+	// build a new request and apply customisations
+	if result.Request, err = factory(); err != nil {
+		result.Request, result.Err = nil, fmt.Errorf("httpx: failed to create request: %v", err)
+		return result
+	}
+
+	for _, fn := range builders {
+		if err = fn(result.Request); err != nil {
+			result.Err = fmt.Errorf("httpx: builder: %v", err)
+			return result
+		}
+	}
+
+	// execute the request
+	var start = time.Now()
+	if result.Response, err = fn(result.Request); err != nil {
+		result.Response, result.Err = nil, fmt.Errorf("httpx: failed to execute request: %v", err)
+	} else if result.Response == nil {
+		result.Err = errors.New("httpx: executor returned no response")
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// fail returns a no-op Assertable that reports the given (failed) result, allowing us to break out of MakeRequest(...) quicker.
+func fail(result *Result) Assertable {
 	return func(t TestingT, _ ...Assertion) {
 		t.Helper()
-		t.Errorf(format, args...)
-		t.FailNow() // doesn't return
+		deliver(t, result) // doesn't return, unless t collects results
 	}
 }
@@ -82,6 +82,14 @@ func TestExecFn_MakeRequest(t *testing.T) {
 		assert(t, 0 == r["FailNow"], "FailNow must not be called")
 	})
 
+	t.Run("should fail if executor returns no response", func(t *testing.T) {
+		r := make(reporter)
+		ExecFn(func(*http.Request) (*http.Response, error) { return nil, nil }).
+			MakeRequest(Get("http://example.com")).ExpectIt(r)
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 1 == r["FailNow"], "FailNow must be called exactly once")
+	})
+
 	t.Run("post request factory", func(t *testing.T) {
 		r := make(reporter)
 		execer(nil).MakeRequest(Post("https://example.com", nil)).ExpectIt(r)
@@ -102,6 +110,13 @@ func TestExecFn_MakeRequest(t *testing.T) {
 		assert(t, 0 == r["Errorf"], "Errorf must not be called")
 		assert(t, 0 == r["FailNow"], "FailNow must not be called")
 	})
+
+	t.Run("options request factory", func(t *testing.T) {
+		r := make(reporter)
+		execer(nil).MakeRequest(Options("https://example.com")).ExpectIt(r)
+		assert(t, 0 == r["Errorf"], "Errorf must not be called")
+		assert(t, 0 == r["FailNow"], "FailNow must not be called")
+	})
 }
 
 type errorReader struct {
@@ -140,3 +155,214 @@ func TestReadResponseBodyMultipleTimes(t *testing.T) {
 		assert(t, 0 == r["FailNow"], "FailNow must not be called")
 	})
 }
+
+func TestAssertable_Evaluate(t *testing.T) {
+	var execer = ExecFn(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("hello"))}, nil
+	})
+
+	t.Run("should return result with outcomes", func(t *testing.T) {
+		var result = execer.MakeRequest(Get("https://example.com")).Evaluate(
+			func(*http.Response) error { return nil },
+			func(*http.Response) error { return errors.New("test") },
+		)
+
+		assert(t, result.Err == nil, "must not have error")
+		assert(t, result.Request != nil && result.Request.URL.Host == "example.com", "must have request")
+		assert(t, result.Response != nil && result.Response.StatusCode == http.StatusOK, "must have response")
+		assert(t, string(result.Body) == "hello", "must have buffered body")
+		assert(t, len(result.Outcomes) == 2, "must have outcome for each assertion")
+		assert(t, result.Outcomes[0].Err == nil && result.Outcomes[1].Err != nil, "must have correct outcomes")
+		assert(t, !result.Passed() && len(result.Failures()) == 1, "must report failures")
+	})
+
+	t.Run("should return result with error", func(t *testing.T) {
+		var result = ExecFn(func(*http.Request) (*http.Response, error) { return nil, errors.New("test") }).
+			MakeRequest(Get("https://example.com")).Evaluate(func(*http.Response) error { return nil })
+
+		assert(t, result.Err != nil, "must have error")
+		assert(t, result.Request != nil && result.Request.URL.Host == "example.com", "must keep request")
+		assert(t, len(result.Outcomes) == 0, "must not run assertions")
+		assert(t, !result.Passed(), "must not pass")
+	})
+
+	t.Run("should keep request if builder fails", func(t *testing.T) {
+		var result = execer.MakeRequest(Get("https://example.com"), func(*http.Request) error {
+			return errors.New("test")
+		}).Evaluate()
+
+		assert(t, result.Err != nil, "must have error")
+		assert(t, result.Request != nil && result.Request.URL.Host == "example.com", "must keep request")
+	})
+
+	t.Run("should support custom assertable", func(t *testing.T) {
+		var result = Assertable(func(t TestingT, _ ...Assertion) { t.Errorf("test") }).Evaluate()
+		assert(t, len(result.Failures()) == 1, "must collect reported errors")
+	})
+
+	t.Run("should see through wrapped TestingT", func(t *testing.T) {
+		var inner = execer.MakeRequest(Get("https://example.com"))
+		var result = Assertable(func(t TestingT, assertions ...Assertion) {
+			inner(&wrapped{t}, assertions...)
+		}).Evaluate(func(*http.Response) error { return errors.New("test") })
+
+		assert(t, result.Response != nil && string(result.Body) == "hello", "must hand over result")
+		assert(t, len(result.Outcomes) == 1 && len(result.Failures()) == 1, "must have outcome for assertion")
+	})
+}
+
+// wrapped is a TestingT that decorates another, as a custom Assertable might
+type wrapped struct{ TestingT }
+
+func (w *wrapped) Errorf(format string, args ...interface{}) {
+	w.TestingT.Errorf("wrapped: "+format, args...)
+}
+
+func (w *wrapped) Unwrap() TestingT { return w.TestingT }
+
+func TestRequire(t *testing.T) {
+	var execer = ExecFn(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("hello"))}, nil
+	})
+
+	var pass = Assertion(func(response *http.Response) error {
+		var body, _ = ioutil.ReadAll(response.Body)
+		if string(body) != "hello" {
+			return errors.New("body must be readable")
+		}
+		return nil
+	})
+	var failing = Assertion(func(*http.Response) error { return errors.New("test") })
+
+	t.Run("should abort remaining assertions on failure", func(t *testing.T) {
+		r := make(reporter)
+		execer.MakeRequest(Get("https://example.com")).ExpectIt(r, Require(pass, failing), failing, failing)
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 1 == r["FailNow"], "FailNow must be called exactly once")
+	})
+
+	t.Run("should continue if required assertions pass", func(t *testing.T) {
+		r := make(reporter)
+		execer.MakeRequest(Get("https://example.com")).ExpectIt(r, Require(pass, pass), pass, failing)
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 0 == r["FailNow"], "FailNow must not be called")
+	})
+
+	t.Run("should mark skipped assertions in result", func(t *testing.T) {
+		var result = execer.MakeRequest(Get("https://example.com")).Evaluate(pass, Require(failing), pass)
+		assert(t, result.Aborted, "result must be aborted")
+		assert(t, IsFatal(result.Outcomes[1].Err), "error must be fatal")
+		assert(t, result.Outcomes[2].Skipped, "remaining assertions must be skipped")
+	})
+}
+
+func TestAssertable_ExpectAll(t *testing.T) {
+	var execer = ExecFn(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	var failing = Assertion(func(*http.Response) error { return errors.New("test") })
+
+	t.Run("should report all failures together", func(t *testing.T) {
+		r := make(reporter)
+		execer.MakeRequest(Get("https://example.com")).ExpectAll(r, failing, failing, func(*http.Response) error { return nil })
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 0 == r["FailNow"], "FailNow must not be called")
+	})
+
+	t.Run("should stop on fatal failures", func(t *testing.T) {
+		r := make(reporter)
+		execer.MakeRequest(Get("https://example.com")).ExpectAll(r, Require(failing), failing)
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 1 == r["FailNow"], "FailNow must be called exactly once")
+	})
+}
+
+// infinite is an endless response body that records how much of it was read and whether it was closed
+type infinite struct {
+	read   int
+	closed bool
+}
+
+func (i *infinite) Read(p []byte) (int, error) {
+	for n := range p {
+		p[n] = 'x'
+	}
+	i.read += len(p)
+	return len(p), nil
+}
+
+func (i *infinite) Close() error {
+	i.closed = true
+	return nil
+}
+
+// streamCheck is a StreamAssertion that records the chunks written to it, and returns the given errors
+type streamCheck struct {
+	written          []byte
+	closed           bool
+	onWrite, onClose error
+}
+
+func (s *streamCheck) Write(p []byte) (int, error) {
+	s.written = append(s.written, p...)
+	return len(p), s.onWrite
+}
+
+func (s *streamCheck) Close() error {
+	s.closed = true
+	return s.onClose
+}
+
+func (s *streamCheck) assertion() StreamAssertion {
+	return func(*http.Response) io.WriteCloser { return s }
+}
+
+func TestExecFn_StreamRequest(t *testing.T) {
+	var execer = func(body io.ReadCloser) ExecFn {
+		return func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+		}
+	}
+
+	t.Run("should feed the whole body to every assertion", func(t *testing.T) {
+		var a, b = &streamCheck{}, &streamCheck{onClose: errors.New("test")}
+		var result = execer(ioutil.NopCloser(bytes.NewBufferString("hello"))).
+			StreamRequest(Get("https://example.com")).Evaluate(a.assertion(), b.assertion())
+
+		assert(t, result.Err == nil, "must not have error")
+		assert(t, result.Body == nil, "must not buffer body")
+		assert(t, string(a.written) == "hello" && string(b.written) == "hello", "must write body to all assertions")
+		assert(t, a.closed && b.closed, "must close all assertions")
+		assert(t, len(result.Outcomes) == 2 && result.Outcomes[0].Err == nil && result.Outcomes[1].Err != nil, "must have correct outcomes")
+	})
+
+	t.Run("should stop reading once all assertions are done", func(t *testing.T) {
+		var body = &infinite{}
+		var satisfied = &streamCheck{onWrite: ErrSatisfied}
+		var failing = &streamCheck{onWrite: errors.New("test")}
+		var result = execer(body).StreamRequest(Get("https://example.com")).Evaluate(satisfied.assertion(), failing.assertion())
+
+		assert(t, body.read > 0 && body.closed, "must read and close body")
+		assert(t, satisfied.closed && failing.closed, "must close all assertions")
+		assert(t, result.Outcomes[0].Err == nil, "must not report satisfied assertion as failure")
+		assert(t, result.Outcomes[1].Err != nil, "must report failed write")
+	})
+
+	t.Run("should fail if body has already been streamed", func(t *testing.T) {
+		var s = execer(ioutil.NopCloser(bytes.NewBufferString("hello"))).StreamRequest(Get("https://example.com"))
+		s.Evaluate()
+
+		r := make(reporter)
+		s.ExpectIt(r, (&streamCheck{}).assertion())
+		assert(t, 1 == r["Errorf"], "Errorf must be called exactly once")
+		assert(t, 1 == r["FailNow"], "FailNow must be called exactly once")
+	})
+
+	t.Run("should fail if request cannot be executed", func(t *testing.T) {
+		var result = ExecFn(func(*http.Request) (*http.Response, error) { return nil, errors.New("test") }).
+			StreamRequest(Get("https://example.com")).Evaluate((&streamCheck{}).assertion())
+
+		assert(t, result.Err != nil, "must have error")
+		assert(t, len(result.Outcomes) == 0, "must not run assertions")
+	})
+}
@@ -0,0 +1,115 @@
+package load
+
+import (
+	"go.riyazali.net/httpx"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Benchmark runs the given scenario b.N times using the given ExecFn, reporting any failures to b.
+// Use it inside a benchmark function to make handler performance regressions visible in go test -bench,
+//
+//  func BenchmarkVersions(b *testing.B) {
+//    load.Benchmark(b, WithHandler(handler), load.Request(Get("/versions")))
+//  }
+//
+// Along with the usual metrics, it reports the size of the response body (body-B/op), the number of
+// allocations made to execute the request and read its body, excluding building the request and running
+// the assertions (exec-allocs/op), and latency percentiles (p50-ns, p95-ns and p99-ns).
+//
+// If the scenario doesn't have any assertions, the response body is drained directly
+// instead of being buffered, keeping the benchmark's own overhead to a minimum.
+func Benchmark(b *testing.B, fn httpx.ExecFn, scenario Scenario) {
+	b.Helper()
+	b.ReportAllocs()
+
+	var report = &Report{Latencies: make([]time.Duration, b.N)}
+	var size int64
+
+	// an extra assertion that counts the bytes in response body
+	var assertions = append(append([]httpx.Assertion(nil), scenario.Assertions...), func(response *http.Response) error {
+		var n, err = io.Copy(ioutil.Discard, response.Body)
+		size += n
+		return err
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var start = time.Now()
+		if len(scenario.Assertions) == 0 {
+			size += drain(b, fn, scenario)
+		} else {
+			fn.MakeRequest(scenario.Request, scenario.Builders...).ExpectIt(b, assertions...)
+		}
+		report.Latencies[i] = time.Since(start)
+	}
+	b.StopTimer()
+
+	sort.Slice(report.Latencies, func(i, j int) bool { return report.Latencies[i] < report.Latencies[j] })
+	b.ReportMetric(float64(size)/float64(b.N), "body-B/op")
+	b.ReportMetric(float64(report.Percentile(50).Nanoseconds()), "p50-ns")
+	b.ReportMetric(float64(report.Percentile(95).Nanoseconds()), "p95-ns")
+	b.ReportMetric(float64(report.Percentile(99).Nanoseconds()), "p99-ns")
+
+	// measure executor's allocations separately, as doing so on every iteration would slow down the benchmark.
+	// Requests are built upfront so that allocations made by the factory and builders aren't counted.
+	const runs = 100
+	var requests = make([]*http.Request, 0, runs+1) // AllocsPerRun does an extra warm-up run
+	for i := 0; i < runs+1; i++ {
+		var request, err = build(scenario)
+		if err != nil {
+			b.Fatalf("load: failed to build request: %v", err)
+		}
+		requests = append(requests, request)
+	}
+
+	var next = 0
+	var allocs = testing.AllocsPerRun(runs, func() {
+		var request = requests[next]
+		next++
+		if response, err := fn(request); err == nil && response != nil {
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+	})
+	b.ReportMetric(allocs, "exec-allocs/op")
+}
+
+// drain executes the scenario once, without buffering the response body, and returns the number of bytes in it
+func drain(b *testing.B, fn httpx.ExecFn, scenario Scenario) int64 {
+	b.Helper()
+	var request, err = build(scenario)
+	if err != nil {
+		b.Fatalf("load: failed to build request: %v", err)
+	}
+
+	var response *http.Response
+	if response, err = fn(request); err != nil || response == nil {
+		b.Fatalf("load: failed to execute request: %v", err)
+	}
+	defer response.Body.Close()
+
+	var n int64
+	if n, err = io.Copy(ioutil.Discard, response.Body); err != nil {
+		b.Fatalf("load: failed to read response body: %v", err)
+	}
+	return n
+}
+
+// build creates a new request for the scenario and applies all builders to it
+func build(scenario Scenario) (*http.Request, error) {
+	var request, err = scenario.Request()
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range scenario.Builders {
+		if err = fn(request); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
@@ -0,0 +1,48 @@
+package load_test
+
+import (
+	"flag"
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/load"
+	"io"
+	"net/http"
+	"testing"
+)
+
+var hello = WithHandlerFn(func(writer http.ResponseWriter, request *http.Request) {
+	_, _ = io.WriteString(writer, "hello world")
+})
+
+func TestBenchmark(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmark in short mode")
+	}
+
+	// a handful of iterations is enough to check the reported metrics
+	var benchtime = flag.Lookup("test.benchtime").Value
+	var previous = benchtime.String()
+	_ = benchtime.Set("50x")
+	defer func() { _ = benchtime.Set(previous) }()
+
+	for name, scenario := range map[string]load.Scenario{
+		"without assertions": load.Request(Get("/")),
+		"with assertions":    load.Request(Get("/")).Expect(ToHaveStatus(http.StatusOK)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var result = testing.Benchmark(func(b *testing.B) {
+				load.Benchmark(b, hello, scenario)
+			})
+
+			assert(t, result.N > 0, "benchmark must run")
+			assert(t, result.Extra["body-B/op"] == 11, "must report body size: %v", result.Extra["body-B/op"])
+			assert(t, result.Extra["p99-ns"] >= result.Extra["p50-ns"], "must report latency percentiles")
+			assert(t, result.Extra["exec-allocs/op"] > 0, "must report executor allocations")
+		})
+	}
+}
+
+func BenchmarkHandler(b *testing.B) {
+	load.Benchmark(b, hello, load.Request(Get("/")).Expect(ToHaveStatus(http.StatusOK)))
+}
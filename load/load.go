@@ -0,0 +1,196 @@
+// Package load allows running httpx requests as load scenarios and asserting on aggregate results.
+//
+// A Scenario captures a request (along with its builders and assertions) so that the same definition
+// can be used both as a functional test and as a load test,
+//
+//  var scenario = load.Request(Get("/versions"), WithHeader("Accept", "application/json")).
+//    Expect(ToHaveStatus(http.StatusOK))
+//
+//  scenario.ExpectIt(t, WithHandler(handler)) // run once, as a functional test
+//
+//  load.Run(WithHandler(handler), scenario, load.Workers(8), load.Duration(5*time.Second)).
+//    ExpectIt(t, load.P95(10*time.Millisecond), load.ErrorRateBelow(0.01))
+//
+// Any ExecFn is a valid target, including an in-memory handler, so load tests can run in CI
+// without any external services.
+package load // import "go.riyazali.net/httpx/load"
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scenario defines a request along with the assertions to run on every response.
+type Scenario struct {
+	Request    httpx.RequestFactory
+	Builders   []httpx.RequestBuilder
+	Assertions []httpx.Assertion
+}
+
+// Request returns a new Scenario with the given RequestFactory and RequestBuilders.
+func Request(factory httpx.RequestFactory, builders ...httpx.RequestBuilder) Scenario {
+	return Scenario{Request: factory, Builders: builders}
+}
+
+// Expect returns a copy of the scenario with the given assertions added to it.
+func (s Scenario) Expect(assertions ...httpx.Assertion) Scenario {
+	s.Assertions = append(append([]httpx.Assertion(nil), s.Assertions...), assertions...)
+	return s
+}
+
+// ExpectIt executes the scenario once using the given ExecFn and reports the results to t,
+// just like a regular MakeRequest(...).ExpectIt(...) call would.
+func (s Scenario) ExpectIt(t httpx.TestingT, fn httpx.ExecFn) {
+	t.Helper()
+	fn.MakeRequest(s.Request, s.Builders...).ExpectIt(t, s.Assertions...)
+}
+
+// Config defines how a scenario is run by Run(...). Use the option functions in this package to customise it.
+type Config struct {
+	// Workers is the number of concurrent workers (defaults to 1)
+	Workers int
+
+	// Duration is the duration for which to run the scenario
+	Duration time.Duration
+
+	// Count is the total number of requests to make. If neither Count nor Duration
+	// is set, the scenario is run once.
+	Count int
+
+	// Rate, if set, is the fixed number of requests per second to make
+	Rate float64
+}
+
+// Workers sets the number of concurrent workers.
+func Workers(n int) func(*Config) {
+	return func(c *Config) {
+		c.Workers = n
+	}
+}
+
+// Duration runs the scenario for the given duration.
+func Duration(d time.Duration) func(*Config) {
+	return func(c *Config) {
+		c.Duration = d
+	}
+}
+
+// Count runs the scenario for the given number of times.
+func Count(n int) func(*Config) {
+	return func(c *Config) {
+		c.Count = n
+	}
+}
+
+// Rate runs the scenario at a fixed arrival rate of the given number of requests per second.
+// The number of in-flight requests is still limited by the number of workers.
+// Rates too high to be represented as an interval between requests (above 1e9/s) fail the run.
+func Rate(rps float64) func(*Config) {
+	return func(c *Config) {
+		c.Rate = rps
+	}
+}
+
+// Run runs the given scenario using the given ExecFn and returns an aggregated Report.
+// When both Count and Duration are set, it stops as soon as either limit is reached.
+func Run(fn httpx.ExecFn, scenario Scenario, opts ...func(*Config)) *Report {
+	var config = Config{Workers: 1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.Count <= 0 && config.Duration <= 0 {
+		config.Count = 1
+	}
+
+	var interval time.Duration
+	if config.Rate > 0 {
+		if interval = time.Duration(float64(time.Second) / config.Rate); interval <= 0 {
+			return &Report{Statuses: make(map[int]int), Err: fmt.Errorf("load: rate of %v requests per second is too high", config.Rate)}
+		}
+	}
+
+	var done = make(chan struct{})
+	if config.Duration > 0 {
+		var timer = time.AfterFunc(config.Duration, func() { close(done) })
+		defer timer.Stop()
+	}
+
+	var tokens <-chan time.Time
+	if interval > 0 {
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+		tokens = ticker.C
+	}
+
+	// next claims the next iteration, returning false once the run is over
+	var issued int64
+	var next = func() bool {
+		if config.Count > 0 && atomic.AddInt64(&issued, 1) > int64(config.Count) {
+			return false
+		}
+		if tokens != nil {
+			select {
+			case <-tokens:
+			case <-done:
+				return false
+			}
+		}
+		select {
+		case <-done:
+			return false
+		default:
+			return true
+		}
+	}
+
+	var report = &Report{Statuses: make(map[int]int)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var start = time.Now()
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next() {
+				var s = run(fn, scenario)
+				mu.Lock()
+				report.add(s)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Elapsed = time.Since(start)
+	sort.Slice(report.Latencies, func(i, j int) bool { return report.Latencies[i] < report.Latencies[j] })
+	return report
+}
+
+// sample is the result of a single execution of the scenario
+type sample struct {
+	status  int
+	latency time.Duration
+	err     string
+}
+
+// run executes the scenario once
+func run(fn httpx.ExecFn, scenario Scenario) (s sample) {
+	var start = time.Now()
+	var result = fn.MakeRequest(scenario.Request, scenario.Builders...).Evaluate(scenario.Assertions...)
+	s.latency = time.Since(start)
+
+	if result.Response != nil {
+		s.status = result.Response.StatusCode
+	}
+	if failures := result.Failures(); len(failures) > 0 {
+		s.err = failures[0].Error()
+	}
+	return s
+}
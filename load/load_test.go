@@ -0,0 +1,98 @@
+package load_test
+
+import (
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/load"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+// TestingT implementation that logs it's method calls
+type reporter map[string]int
+
+func (r reporter) Errorf(_ string, _ ...interface{}) { r["Errorf"] = r["Errorf"] + 1 }
+func (r reporter) FailNow()                          { r["FailNow"] = r["FailNow"] + 1 }
+func (r reporter) Helper()                           {}
+
+func TestRun(t *testing.T) {
+	var calls int64
+	var handler = WithHandlerFn(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.AddInt64(&calls, 1)%4 == 0 {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	var scenario = load.Request(Get("/")).Expect(ToHaveStatus(http.StatusOK))
+
+	t.Run("should run for given count", func(t *testing.T) {
+		atomic.StoreInt64(&calls, 0)
+		var report = load.Run(handler, scenario, load.Workers(4), load.Count(100))
+
+		assert(t, report.Requests == 100, "must make exactly 100 requests: %d", report.Requests)
+		assert(t, report.Failures == 25, "must count failed assertions: %d", report.Failures)
+		assert(t, report.Statuses[http.StatusOK] == 75, "must count statuses")
+		assert(t, report.ErrorRate() == 0.25, "must compute error rate")
+		assert(t, len(report.Latencies) == 100, "must record all latencies")
+
+		var r = make(reporter)
+		report.ExpectIt(r, load.ErrorRateBelow(0.3), load.P99(time.Second), load.ErrorRateBelow(0.1))
+		assert(t, r["Errorf"] == 1, "must report failed checks")
+	})
+
+	t.Run("should run for given duration", func(t *testing.T) {
+		var report = load.Run(handler, scenario, load.Workers(2), load.Duration(50*time.Millisecond))
+		assert(t, report.Requests > 0, "must make requests")
+		assert(t, report.Elapsed >= 50*time.Millisecond, "must run for given duration")
+	})
+
+	t.Run("should run at given rate", func(t *testing.T) {
+		var report = load.Run(handler, scenario, load.Workers(4), load.Rate(200), load.Duration(100*time.Millisecond))
+		assert(t, report.Requests > 0 && report.Requests <= 21, "must respect arrival rate: %d", report.Requests)
+	})
+
+	t.Run("should fail for rate that's too high", func(t *testing.T) {
+		var report = load.Run(handler, scenario, load.Rate(2e9), load.Count(1))
+		assert(t, report.Err != nil && report.Requests == 0, "must fail without making requests")
+
+		var r = make(reporter)
+		report.ExpectIt(r, load.ErrorRateBelow(0.1))
+		assert(t, r["Errorf"] == 1 && r["FailNow"] == 1, "must report error and stop")
+	})
+
+	t.Run("should run once by default", func(t *testing.T) {
+		var report = load.Run(handler, load.Request(Get("/")))
+		assert(t, report.Requests == 1, "must make exactly one request")
+	})
+}
+
+func TestReport(t *testing.T) {
+	var report = &load.Report{Requests: 10, Elapsed: time.Second}
+	for i := 1; i <= 10; i++ {
+		report.Latencies = append(report.Latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	assert(t, report.Percentile(50) == 5*time.Millisecond, "must compute p50")
+	assert(t, report.Percentile(95) == 10*time.Millisecond, "must compute p95")
+	assert(t, report.Throughput() == 10, "must compute throughput")
+
+	var r = make(reporter)
+	report.ExpectIt(r, load.P50(5*time.Millisecond), load.P95(time.Millisecond), load.ThroughputAbove(100))
+	assert(t, r["Errorf"] == 2, "must report failed checks")
+}
+
+func TestScenario_ExpectIt(t *testing.T) {
+	var r = make(reporter)
+	load.Request(Get("/")).Expect(ToHaveStatus(http.StatusNotFound)).
+		ExpectIt(r, WithHandler(http.NotFoundHandler()))
+	assert(t, r["Errorf"] == 0, "must run scenario as functional test")
+}
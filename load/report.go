@@ -0,0 +1,138 @@
+package load
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"math"
+	"strings"
+	"time"
+)
+
+// Report contains the aggregated results of running a scenario.
+type Report struct {
+	// Requests is the total number of requests made
+	Requests int
+
+	// Failures is the number of requests that either failed to execute or failed an assertion
+	Failures int
+
+	// Statuses is the number of responses received per status code.
+	// Requests that failed to execute are counted against status 0.
+	Statuses map[int]int
+
+	// Errors is the number of times each distinct error was reported
+	Errors map[string]int
+
+	// Latencies of all the requests, sorted in ascending order
+	Latencies []time.Duration
+
+	// Elapsed is the total wall-clock time taken by the run
+	Elapsed time.Duration
+
+	// Err is set if the run could not be started (say, because of an invalid configuration).
+	// No requests are made in that case.
+	Err error
+}
+
+func (r *Report) add(s sample) {
+	r.Requests++
+	r.Statuses[s.status]++
+	r.Latencies = append(r.Latencies, s.latency)
+	if s.err != "" {
+		r.Failures++
+		if r.Errors == nil {
+			r.Errors = make(map[string]int)
+		}
+		r.Errors[s.err]++
+	}
+}
+
+// Percentile returns the p-th (0 < p <= 100) percentile latency, using the nearest-rank method.
+func (r *Report) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	var rank = int(math.Ceil(p / 100 * float64(len(r.Latencies))))
+	if rank < 1 {
+		rank = 1
+	} else if rank > len(r.Latencies) {
+		rank = len(r.Latencies)
+	}
+	return r.Latencies[rank-1]
+}
+
+// ErrorRate returns the fraction of requests that failed
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Failures) / float64(r.Requests)
+}
+
+// Throughput returns the number of requests made per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// String returns a short, human readable summary of the report
+func (r *Report) String() string {
+	var buf strings.Builder
+	_, _ = fmt.Fprintf(&buf, "requests=%d failures=%d (%.2f%%) throughput=%.2f/s ",
+		r.Requests, r.Failures, r.ErrorRate()*100, r.Throughput())
+	_, _ = fmt.Fprintf(&buf, "p50=%v p95=%v p99=%v", r.Percentile(50), r.Percentile(95), r.Percentile(99))
+	return buf.String()
+}
+
+// ExpectIt runs the given checks on the report and reports any failures to t.
+// If the run could not be started, its error is reported instead and the test is stopped using t.FailNow().
+func (r *Report) ExpectIt(t httpx.TestingT, checks ...Check) {
+	t.Helper()
+	if r.Err != nil {
+		t.Errorf("%v", r.Err)
+		t.FailNow() // doesn't return
+		return
+	}
+	for _, fn := range checks {
+		if err := fn(r); err != nil {
+			t.Errorf("load: %v (%s)", err, r)
+		}
+	}
+}
+
+// Check defines a function that performs some assertion on the aggregated results of a run.
+type Check func(*Report) error
+
+// LatencyPercentile returns a Check that asserts that the p-th percentile latency is at most max.
+func LatencyPercentile(p float64, max time.Duration) Check {
+	return func(r *Report) error {
+		var actual = r.Percentile(p)
+		return AssertThat(actual <= max, "p%v latency (%v) greater than expected (%v)", p, actual, max)
+	}
+}
+
+// P50 is a shorthand for LatencyPercentile(50, max)
+func P50(max time.Duration) Check { return LatencyPercentile(50, max) }
+
+// P95 is a shorthand for LatencyPercentile(95, max)
+func P95(max time.Duration) Check { return LatencyPercentile(95, max) }
+
+// P99 is a shorthand for LatencyPercentile(99, max)
+func P99(max time.Duration) Check { return LatencyPercentile(99, max) }
+
+// ErrorRateBelow returns a Check that asserts that the error rate is at most the given rate (between 0 and 1).
+func ErrorRateBelow(rate float64) Check {
+	return func(r *Report) error {
+		return AssertThat(r.ErrorRate() <= rate, "error rate (%.4f) greater than expected (%.4f)", r.ErrorRate(), rate)
+	}
+}
+
+// ThroughputAbove returns a Check that asserts that the throughput is at least the given requests per second.
+func ThroughputAbove(rps float64) Check {
+	return func(r *Report) error {
+		return AssertThat(r.Throughput() >= rps, "throughput (%.2f/s) less than expected (%.2f/s)", r.Throughput(), rps)
+	}
+}
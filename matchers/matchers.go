@@ -0,0 +1,302 @@
+// Package matchers provides a set of generic matchers for use inside assertions.
+//
+// A Matcher checks a single value and, unlike a hand-written AssertThat(...), generates
+// a descriptive failure message on its own, like,
+//
+//    expected "text/html" to have prefix "application/"
+//
+// Matchers can be used with assertions that accept them (like HeaderThat(...), CookieThat(...) and JsonPath(...)
+// in the assertions package) or be invoked directly inside custom assertions,
+//
+//    BodyJson(func(x X) error {
+//        return Multiple(
+//            matchers.Equal("john")(x.Name),
+//            matchers.Between(18, 99)(x.Age),
+//        )
+//    })
+package matchers // import "go.riyazali.net/httpx/matchers"
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matcher defines a function that matches the given value and returns an error describing the mismatch (if any).
+type Matcher func(actual interface{}) error
+
+// Equal returns a Matcher that checks whether the value is deeply equal to the expected value.
+// Numeric values are compared by their value, irrespective of their type, so that Equal(1) matches
+// json-decoded float64(1) too.
+func Equal(expected interface{}) Matcher {
+	return func(actual interface{}) error {
+		return check(equal(expected, actual), "expected %s to equal %s", describe(actual), describe(expected))
+	}
+}
+
+// Nil returns a Matcher that checks whether the value is nil.
+func Nil() Matcher {
+	return func(actual interface{}) error {
+		return check(isNil(actual), "expected %s to be nil", describe(actual))
+	}
+}
+
+// Empty returns a Matcher that checks whether the value is nil, or the zero value of its type, or has zero length.
+func Empty() Matcher {
+	return func(actual interface{}) error {
+		var empty = isNil(actual)
+		if !empty {
+			var v = reflect.ValueOf(actual)
+			switch v.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+				empty = v.Len() == 0
+			default:
+				empty = v.IsZero()
+			}
+		}
+		return check(empty, "expected %s to be empty", describe(actual))
+	}
+}
+
+// Contains returns a Matcher that checks whether the value contains the given element. Strings are checked
+// for the given substring, whereas slices and arrays are checked for an element that is Equal(...) to it.
+func Contains(element interface{}) Matcher {
+	return func(actual interface{}) error {
+		var found bool
+		if s, ok := actual.(string); ok {
+			var sub, isString = element.(string)
+			found = isString && strings.Contains(s, sub)
+		} else if v := reflect.ValueOf(actual); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for i := 0; i < v.Len() && !found; i++ {
+				found = equal(element, v.Index(i).Interface())
+			}
+		} else {
+			return fmt.Errorf("expected %s to be a string, slice or array", describe(actual))
+		}
+		return check(found, "expected %s to contain %s", describe(actual), describe(element))
+	}
+}
+
+// HasPrefix returns a Matcher that checks whether the value is a string with the given prefix.
+func HasPrefix(prefix string) Matcher {
+	return stringMatcher(func(s string) error {
+		return check(strings.HasPrefix(s, prefix), "expected %q to have prefix %q", s, prefix)
+	})
+}
+
+// HasSuffix returns a Matcher that checks whether the value is a string with the given suffix.
+func HasSuffix(suffix string) Matcher {
+	return stringMatcher(func(s string) error {
+		return check(strings.HasSuffix(s, suffix), "expected %q to have suffix %q", s, suffix)
+	})
+}
+
+// MatchesRegexp returns a Matcher that checks whether the value is a string that matches the given regular expression.
+// Panics if the expression cannot be compiled.
+func MatchesRegexp(expr string) Matcher {
+	var re = regexp.MustCompile(expr)
+	return stringMatcher(func(s string) error {
+		return check(re.MatchString(s), "expected %q to match %q", s, expr)
+	})
+}
+
+// GreaterThan returns a Matcher that checks whether the value is a number greater than n.
+// Strings are parsed as numbers, allowing use with header values.
+func GreaterThan(n interface{}) Matcher {
+	return numberMatcher(func(a float64, desc string) error {
+		var b, err = number(n)
+		if err != nil {
+			return err
+		}
+		return check(a > b, "expected %s to be greater than %v", desc, n)
+	})
+}
+
+// LessThan returns a Matcher that checks whether the value is a number less than n.
+// Strings are parsed as numbers, allowing use with header values.
+func LessThan(n interface{}) Matcher {
+	return numberMatcher(func(a float64, desc string) error {
+		var b, err = number(n)
+		if err != nil {
+			return err
+		}
+		return check(a < b, "expected %s to be less than %v", desc, n)
+	})
+}
+
+// Between returns a Matcher that checks whether the value is a number between min and max (both inclusive).
+// Strings are parsed as numbers, allowing use with header values.
+func Between(min, max interface{}) Matcher {
+	return numberMatcher(func(a float64, desc string) error {
+		var lo, err1 = number(min)
+		var hi, err2 = number(max)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("expected bounds (%v, %v) to be numbers", min, max)
+		}
+		return check(a >= lo && a <= hi, "expected %s to be between %v and %v", desc, min, max)
+	})
+}
+
+// Len returns a Matcher that checks whether the value (a string, slice, array or map) has the given length.
+func Len(n int) Matcher {
+	return func(actual interface{}) error {
+		var v = reflect.ValueOf(actual)
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+			return check(v.Len() == n, "expected %s to have length %d but has length %d", describe(actual), n, v.Len())
+		default:
+			return fmt.Errorf("expected %s to be a string, slice, array or map", describe(actual))
+		}
+	}
+}
+
+// HasKey returns a Matcher that checks whether the value is a map that contains the given key.
+func HasKey(key interface{}) Matcher {
+	return func(actual interface{}) error {
+		var v = reflect.ValueOf(actual)
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("expected %s to be a map", describe(actual))
+		}
+		for _, k := range v.MapKeys() {
+			if equal(key, k.Interface()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected %s to have key %s", describe(actual), describe(key))
+	}
+}
+
+// ElementsMatch returns a Matcher that checks whether the value is a slice (or array) that contains
+// exactly the same elements as the expected slice (or array), ignoring their order.
+func ElementsMatch(expected interface{}) Matcher {
+	return func(actual interface{}) error {
+		var a, e = reflect.ValueOf(actual), reflect.ValueOf(expected)
+		if (a.Kind() != reflect.Slice && a.Kind() != reflect.Array) || (e.Kind() != reflect.Slice && e.Kind() != reflect.Array) {
+			return fmt.Errorf("expected both %s and %s to be slices or arrays", describe(actual), describe(expected))
+		}
+
+		var matched = make([]bool, a.Len())
+		var ok = a.Len() == e.Len()
+		for i := 0; i < e.Len() && ok; i++ {
+			ok = false
+			for j := 0; j < a.Len(); j++ {
+				if !matched[j] && equal(e.Index(i).Interface(), a.Index(j).Interface()) {
+					matched[j], ok = true, true
+					break
+				}
+			}
+		}
+		return check(ok, "expected elements of %s to match %s", describe(actual), describe(expected))
+	}
+}
+
+// Not returns a Matcher that inverts the given matcher.
+func Not(m Matcher) Matcher {
+	return func(actual interface{}) error {
+		return check(m(actual) != nil, "expected %s to not match", describe(actual))
+	}
+}
+
+// AllOf returns a Matcher that checks the value against all of the given matchers, failing on the first mismatch.
+func AllOf(matchers ...Matcher) Matcher {
+	return func(actual interface{}) error {
+		for _, m := range matchers {
+			if err := m(actual); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// check returns an error with the given message if cond is false
+func check(cond bool, format string, args ...interface{}) error {
+	if !cond {
+		return fmt.Errorf(format, args...)
+	}
+	return nil
+}
+
+// describe returns a short, human readable representation of v for use in failure messages
+func describe(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// stringMatcher returns a Matcher that invokes fn if the value is a string
+func stringMatcher(fn func(string) error) Matcher {
+	return func(actual interface{}) error {
+		if s, ok := actual.(string); ok {
+			return fn(s)
+		}
+		return fmt.Errorf("expected %s to be a string", describe(actual))
+	}
+}
+
+// numberMatcher returns a Matcher that invokes fn if the value is (or can be parsed as) a number
+func numberMatcher(fn func(float64, string) error) Matcher {
+	return func(actual interface{}) error {
+		var n, err = number(actual)
+		if err != nil {
+			return err
+		}
+		return fn(n, describe(actual))
+	}
+}
+
+// number converts the given value into a float64
+func number(v interface{}) (float64, error) {
+	var rv = reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("expected %s to be a number", describe(v))
+}
+
+// equal reports whether a and b are deeply equal, comparing numbers by value
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if isNumeric(a) && isNumeric(b) {
+		var x, _ = number(a)
+		var y, _ = number(b)
+		return x == y
+	}
+	return false
+}
+
+func isNumeric(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	var rv = reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
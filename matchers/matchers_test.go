@@ -0,0 +1,101 @@
+package matchers_test
+
+import (
+	. "go.riyazali.net/httpx/matchers"
+	"testing"
+)
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+// pass / fail are handy utility methods to check matchers
+func pass(t *testing.T, m Matcher, v interface{}) {
+	t.Helper()
+	if err := m(v); err != nil {
+		t.Errorf("matcher must pass for %v: %v", v, err)
+	}
+}
+
+func fail(t *testing.T, m Matcher, v interface{}) {
+	t.Helper()
+	if err := m(v); err == nil {
+		t.Errorf("matcher must fail for %v", v)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	pass(t, Equal("a"), "a")
+	pass(t, Equal(1), float64(1))
+	pass(t, Equal([]int{1, 2}), []int{1, 2})
+	fail(t, Equal("a"), "b")
+	fail(t, Equal(1), "1")
+
+	var err = Equal("a")("b")
+	assert(t, err.Error() == `expected "b" to equal "a"`, "must generate descriptive message: %v", err)
+}
+
+func TestNilAndEmpty(t *testing.T) {
+	pass(t, Nil(), nil)
+	pass(t, Nil(), []int(nil))
+	fail(t, Nil(), 0)
+
+	pass(t, Empty(), "")
+	pass(t, Empty(), map[string]int{})
+	pass(t, Empty(), 0)
+	fail(t, Empty(), []int{1})
+}
+
+func TestContains(t *testing.T) {
+	pass(t, Contains("json"), "application/json")
+	pass(t, Contains(2), []interface{}{float64(1), float64(2)})
+	fail(t, Contains("xml"), "application/json")
+	fail(t, Contains(3), []int{1, 2})
+	fail(t, Contains(1), 1)
+}
+
+func TestStrings(t *testing.T) {
+	pass(t, HasPrefix("application/"), "application/json")
+	fail(t, HasPrefix("text/"), "application/json")
+	pass(t, HasSuffix("json"), "application/json")
+	fail(t, HasSuffix("xml"), "application/json")
+	pass(t, MatchesRegexp(`^\d+$`), "123")
+	fail(t, MatchesRegexp(`^\d+$`), "abc")
+	fail(t, HasPrefix("a"), 1)
+}
+
+func TestNumbers(t *testing.T) {
+	pass(t, GreaterThan(1), 2)
+	pass(t, GreaterThan(1), "2")
+	fail(t, GreaterThan(2), 2)
+	pass(t, LessThan(2), 1.5)
+	fail(t, LessThan(2), uint(3))
+	pass(t, Between(1, 3), 3)
+	fail(t, Between(1, 3), 4)
+	fail(t, GreaterThan(1), "abc")
+}
+
+func TestCollections(t *testing.T) {
+	pass(t, Len(2), []int{1, 2})
+	pass(t, Len(3), "abc")
+	fail(t, Len(1), map[string]int{})
+	fail(t, Len(1), 1)
+
+	pass(t, HasKey("a"), map[string]int{"a": 1})
+	fail(t, HasKey("b"), map[string]int{"a": 1})
+	fail(t, HasKey("a"), []int{})
+
+	pass(t, ElementsMatch([]int{1, 2, 2}), []int{2, 1, 2})
+	fail(t, ElementsMatch([]int{1, 2, 2}), []int{1, 1, 2})
+	fail(t, ElementsMatch([]int{1}), []int{1, 2})
+}
+
+func TestComposition(t *testing.T) {
+	pass(t, Not(Equal("a")), "b")
+	fail(t, Not(Equal("a")), "a")
+	pass(t, AllOf(HasPrefix("a"), HasSuffix("c")), "abc")
+	fail(t, AllOf(HasPrefix("a"), HasSuffix("b")), "abc")
+}
@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/assertions"
+	. "go.riyazali.net/httpx/helpers"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Query returns an assertion that checks whether the request has a query parameter with the given value.
+// Like all assertions passed to Server.On(...), it is invoked with the request presented as a response.
+func Query(key, value string) httpx.Assertion {
+	return func(response *http.Response) error {
+		for _, v := range response.Request.URL.Query()[key] {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("query: '%s' does not have value '%s'", key, value)
+	}
+}
+
+// JsonBody returns an assertion that checks whether the request body is a json document
+// that contains the given subset. The subset is serialized using encoding/json, and then every
+// field in it (recursively) must be present with the same value in the request body.
+// Arrays must match element-by-element, each element being a subset of its counterpart.
+func JsonBody(subset interface{}) httpx.Assertion {
+	var expected, err = normalize(subset)
+	return assertions.BodyBytes(func(body []byte) error {
+		if err != nil {
+			return fmt.Errorf("json: failed to serialize subset: %v", err)
+		}
+		var actual interface{}
+		if err := json.Unmarshal(body, &actual); err != nil {
+			return fmt.Errorf("json: failed to decode request body: %v", err)
+		}
+		return AssertThat(isSubset(expected, actual), "json: request body does not contain expected subset")
+	})
+}
+
+// asResponse presents the given call as an *http.Response, so that assertions written for responses
+// (such as the ones in the assertions package) can be used to match requests. The response carries
+// the request's headers and (a fresh reader over its) body, and the request itself as its Request.
+func asResponse(call *Call) *http.Response {
+	return &http.Response{
+		Proto: call.Request.Proto, ProtoMajor: call.Request.ProtoMajor, ProtoMinor: call.Request.ProtoMinor,
+		Header:        call.Request.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(call.Body)),
+		ContentLength: int64(len(call.Body)),
+		Request:       call.Request,
+	}
+}
+
+// normalize converts the given value into its generic json representation
+func normalize(v interface{}) (interface{}, error) {
+	var buf, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(buf, &out)
+	return out, err
+}
+
+// isSubset reports whether expected is a (recursive) subset of actual
+func isSubset(expected, actual interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		var a, ok = actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range e {
+			if av, ok := a[k]; !ok || !isSubset(v, av) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		var a, ok = actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !isSubset(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+// matchPath matches the given path against the pattern, returning any captured parameters.
+func matchPath(pattern, p string) (map[string]string, bool) {
+	var expected = strings.Split(strings.Trim(pattern, "/"), "/")
+	var actual = strings.Split(strings.Trim(p, "/"), "/")
+	if len(expected) != len(actual) {
+		return nil, false
+	}
+
+	var params = make(map[string]string)
+	for i, segment := range expected {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = actual[i]
+		} else if ok, _ := path.Match(segment, actual[i]); !ok {
+			return nil, false
+		}
+	}
+	return params, true
+}
@@ -0,0 +1,280 @@
+// Package mock provides a programmable http server to stand in for downstream services in tests.
+//
+// Register stubs that match incoming requests on method, path, query, headers and body, program
+// canned (or templated) responses for them and, at the end of the test, verify that the expected
+// calls were made. The server runs in-process (using httptest) and so everything runs offline.
+//
+// Stubs use the same vocabulary as the rest of httpx: requests are matched using httpx.Assertion (so most of
+// the assertions package works on requests too) and replies are built using httpx.RequestBuilder (see the builders package).
+//
+//  var server = mock.NewServer(t)
+//  server.On(http.MethodGet, "/users/{id}", assertions.ToHaveHeader("Accept", "application/json")).
+//    Respond(http.StatusOK, builders.WithJson(user)).
+//    Times(1)
+//
+//  // point your code at server.URL ...
+package mock // import "go.riyazali.net/httpx/mock"
+
+import (
+	"fmt"
+	"go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/executors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Server is a mock http server. Use NewServer(...) to create and start a new instance.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	stubs   []*Stub
+	calls   []*Call
+	ordered bool
+}
+
+// NewServer creates and starts a new mock server. The server is shut down, and all expectations
+// are verified (see Verify(...)), when the test completes.
+func NewServer(t executors.CleanupT) *Server {
+	t.Helper()
+	var server = &Server{}
+	server.Server = httptest.NewServer(server)
+	t.Cleanup(func() {
+		t.Helper()
+		server.Close()
+		server.Verify(t)
+	})
+	return server
+}
+
+// On registers a new stub that matches requests with the given method and path pattern
+// and all the given assertions. Stubs are matched in order of their registration.
+//
+// The assertions are invoked with the request presented as an *http.Response, that carries the request's
+// headers and body, and the request itself as its Request. This allows assertions such as ToHaveHeader(...),
+// BodyJson(...) and JsonPath(...) from the assertions package to be used to match requests.
+//
+// The pattern is matched segment by segment. A segment of form {name} matches any value and captures it
+// as a path parameter, whereas other segments are matched using path.Match(...) and so can contain wildcards.
+// Use "*" as method to match any method.
+func (s *Server) On(method, pattern string, assertions ...httpx.Assertion) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stub = &Stub{server: s, method: method, pattern: pattern, assertions: assertions, reply: reply{status: http.StatusOK}, times: -1}
+	s.stubs = append(s.stubs, stub)
+	return stub
+}
+
+// InOrder makes Verify(...) also check that the stubs were called in order of their registration.
+func (s *Server) InOrder() *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ordered = true
+	return s
+}
+
+// Calls returns all the calls received by the server, including the ones that didn't match any stub.
+func (s *Server) Calls() []*Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Call(nil), s.calls...)
+}
+
+// Verify checks that all stubs with an expected number of calls were called exactly that many times,
+// that there were no unmatched calls and, if configured with InOrder(), that calls happened in order.
+// It also reports stubs whose reply could not be programmed (say, because a builder failed).
+func (s *Server) Verify(t httpx.TestingT) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stub := range s.stubs {
+		if stub.reply.err != nil {
+			t.Errorf("mock: %s: invalid reply: %v", stub, stub.reply.err)
+		}
+		if stub.times >= 0 && stub.calls != stub.times {
+			t.Errorf("mock: %s: expected %d call(s) but got %d", stub, stub.times, stub.calls)
+		}
+	}
+
+	var last = -1
+	for _, call := range s.calls {
+		if call.Stub == nil {
+			t.Errorf("mock: unexpected call: %s %s", call.Request.Method, call.Request.URL)
+			continue
+		}
+		if s.ordered {
+			var idx = s.indexOf(call.Stub)
+			if idx < last {
+				t.Errorf("mock: %s: called out of order", call.Stub)
+			}
+			last = idx
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler. It finds a matching stub and writes the programmed reply.
+// If no stub matches, it responds with http.StatusNotImplemented.
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var body, err = ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("mock: failed to read request body: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var call = &Call{Request: request, Body: body}
+	s.mu.Lock()
+	for _, stub := range s.stubs {
+		if params, ok := stub.match(call); ok {
+			call.Stub, call.Params = stub, params
+			stub.calls++
+			break
+		}
+	}
+	s.calls = append(s.calls, call)
+	s.mu.Unlock()
+
+	if call.Stub == nil {
+		var msg = fmt.Sprintf("mock: no stub matches %s %s", request.Method, request.URL)
+		http.Error(writer, msg, http.StatusNotImplemented)
+		return
+	}
+
+	s.mu.Lock()
+	var reply = call.Stub.reply
+	s.mu.Unlock()
+
+	reply.write(writer, call)
+}
+
+func (s *Server) indexOf(stub *Stub) int {
+	for i := range s.stubs {
+		if s.stubs[i] == stub {
+			return i
+		}
+	}
+	return -1
+}
+
+// Call represents a single request received by the server.
+type Call struct {
+	// Stub that matched the call, or nil if no stub matched.
+	Stub *Stub
+
+	// Request received by the server. Use Body to access the request body.
+	Request *http.Request
+
+	// Body is the (buffered) body of the request.
+	Body []byte
+
+	// Params are the path parameters captured by the stub's pattern.
+	Params map[string]string
+}
+
+// Stub defines a programmed request / response pair. Use Server.On(...) to create a new instance.
+type Stub struct {
+	server     *Server
+	method     string
+	pattern    string
+	assertions []httpx.Assertion
+	reply      reply
+
+	times int // expected number of calls (or -1 if unlimited)
+	calls int // actual number of calls
+}
+
+// Respond programs the stub to reply with the given status. The reply's headers and body are built
+// by applying the given builders (eg. builders.WithHeader(...) or builders.WithJson(...)) to a blank request.
+// If a builder fails, the stub replies with http.StatusInternalServerError and Server.Verify(...) reports the error.
+func (st *Stub) Respond(status int, builders ...httpx.RequestBuilder) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+	st.reply.status, st.reply.err = status, nil // reprogramming replaces any earlier error
+	st.reply.build(builders)
+	return st
+}
+
+// Template sets the body of the reply to the output of the given text/template. The template is
+// executed with a *TemplateData for every call, allowing replies to echo values from the request.
+//
+//  server.On(http.MethodPost, "/users/{id}").
+//    Respond(http.StatusCreated, builders.WithHeader("Content-Type", "application/json")).
+//    Template(`{"id": "{{ .Params.id }}", "name": "{{ .Json.name }}"}`)
+//
+// If the template cannot be parsed, the stub replies with http.StatusInternalServerError and Server.Verify(...) reports the error.
+func (st *Stub) Template(text string) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+	var tmpl, err = template.New("reply").Parse(text)
+	if err != nil {
+		st.reply.err = fmt.Errorf("template: %v", err)
+		return st
+	}
+	st.reply.template = tmpl
+	return st
+}
+
+// Delay delays the reply by the given duration (or until the request is cancelled).
+func (st *Stub) Delay(d time.Duration) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+	st.reply.delay = d
+	return st
+}
+
+// Times sets the expected number of calls to the stub. Once the stub has been called n times,
+// it no longer matches any request, allowing subsequent stubs to program follow-up replies.
+func (st *Stub) Times(n int) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+	st.times = n
+	return st
+}
+
+// Once is a shorthand for Times(1)
+func (st *Stub) Once() *Stub {
+	return st.Times(1)
+}
+
+// String returns a human readable description of the stub
+func (st *Stub) String() string {
+	return fmt.Sprintf("stub(%s %s)", st.method, st.pattern)
+}
+
+// match checks whether the stub matches the given call and returns the captured path parameters.
+// must be called with server's lock held.
+func (st *Stub) match(call *Call) (map[string]string, bool) {
+	if st.times >= 0 && st.calls >= st.times {
+		return nil, false
+	}
+	if st.method != "*" && !strings.EqualFold(st.method, call.Request.Method) {
+		return nil, false
+	}
+
+	var params, ok = matchPath(st.pattern, call.Request.URL.Path)
+	if !ok {
+		return nil, false
+	}
+
+	for _, fn := range st.assertions {
+		if err := fn(asResponse(call)); err != nil { // every assertion gets a fresh copy of the body
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// sleep waits for the given duration or until the request is cancelled
+func sleep(request *http.Request, d time.Duration) {
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-request.Context().Done():
+	}
+}
@@ -0,0 +1,181 @@
+package mock_test
+
+import (
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	"go.riyazali.net/httpx/builders"
+	. "go.riyazali.net/httpx/executors"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"go.riyazali.net/httpx/mock"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestingT implementation that logs it's method calls
+type reporter map[string]int
+
+func (r reporter) Errorf(_ string, _ ...interface{}) { r["Errorf"] = r["Errorf"] + 1 }
+func (r reporter) FailNow()                          { r["FailNow"] = r["FailNow"] + 1 }
+func (r reporter) Helper()                           {}
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should reply with programmed response", func(t *testing.T) {
+		var server = mock.NewServer(t)
+		server.On(http.MethodGet, "/users/{id}", ToHaveHeader("Accept", "application/json"), mock.Query("v", "2")).
+			Respond(http.StatusOK, builders.WithJson(map[string]string{"name": "john"}), builders.WithHeader("X-Test", "1")).
+			Once()
+
+		WithDefaultClient().MakeRequest(
+			Get(Url(server.URL, WithPath("users", "1"), WithQueryParam("v", "2"))),
+			builders.WithHeader("Accept", "application/json"),
+		).ExpectIt(t,
+			ToHaveStatus(http.StatusOK),
+			HaveHeader("X-Test"),
+			BodyJson(func(m map[string]string) error {
+				return AssertThat(m["name"] == "john", "name must be john")
+			}),
+		)
+	})
+
+	t.Run("should reply using template", func(t *testing.T) {
+		var server = mock.NewServer(t)
+		server.On(http.MethodPost, "/users/{id}").
+			Respond(http.StatusCreated).
+			Template(`{"id": "{{ .Params.id }}", "name": "{{ .Json.name }}"}`)
+
+		WithDefaultClient().MakeRequest(
+			Post(server.URL+"/users/42", SerializeJson(map[string]string{"name": "jane"})),
+		).ExpectIt(t,
+			ToHaveStatus(http.StatusCreated),
+			BodyJson(func(m map[string]string) error {
+				return Multiple(
+					AssertThat(m["id"] == "42", "id must be echoed from path"),
+					AssertThat(m["name"] == "jane", "name must be echoed from body"),
+				)
+			}),
+		)
+
+		var body, _ = ioutil.ReadAll(server.Calls()[0].Request.Body)
+		assert(t, len(body) == 0, "must not modify recorded request")
+	})
+
+	t.Run("should match json body subset", func(t *testing.T) {
+		var server = mock.NewServer(t)
+		server.On(http.MethodPost, "/orders", mock.JsonBody(map[string]interface{}{"items": []interface{}{map[string]int{"qty": 2}}})).
+			Respond(http.StatusAccepted).
+			Once()
+
+		var body = map[string]interface{}{"id": 1, "items": []interface{}{map[string]interface{}{"sku": "a", "qty": 2}}}
+		WithDefaultClient().
+			MakeRequest(Post(server.URL+"/orders", SerializeJson(body))).
+			ExpectIt(t, ToHaveStatus(http.StatusAccepted))
+	})
+
+	t.Run("should match using assertions", func(t *testing.T) {
+		var server = mock.NewServer(t)
+		server.On(http.MethodPost, "/orders", JsonPath("items[0].qty", matchers.Equal(2))).Respond(http.StatusAccepted)
+		server.On(http.MethodPost, "/orders").Respond(http.StatusBadRequest).Once()
+
+		var body = map[string]interface{}{"items": []interface{}{map[string]interface{}{"qty": 1}}}
+		WithDefaultClient().
+			MakeRequest(Post(server.URL+"/orders", SerializeJson(body))).
+			ExpectIt(t, ToHaveStatus(http.StatusBadRequest))
+	})
+
+	t.Run("should delay reply", func(t *testing.T) {
+		var server = mock.NewServer(t)
+		server.On("*", "/slow/*").Respond(http.StatusOK).Delay(50 * time.Millisecond)
+
+		var start = time.Now()
+		WithDefaultClient().MakeRequest(Get(server.URL+"/slow/a")).ExpectIt(t, ToHaveStatus(http.StatusOK))
+		assert(t, time.Since(start) >= 50*time.Millisecond, "reply must be delayed")
+	})
+}
+
+func TestServer_Verify(t *testing.T) {
+	t.Run("should report unmatched calls", func(t *testing.T) {
+		var r = &cleaner{reporter: make(reporter)}
+		var server = mock.NewServer(r)
+		server.On(http.MethodGet, "/a")
+
+		WithDefaultClient().MakeRequest(Get(server.URL+"/b")).ExpectIt(t, ToHaveStatus(http.StatusNotImplemented))
+
+		r.cleanup()
+		assert(t, r.reporter["Errorf"] == 1, "must report unmatched call")
+	})
+
+	t.Run("should report unmet expectations", func(t *testing.T) {
+		var r = &cleaner{reporter: make(reporter)}
+		var server = mock.NewServer(r)
+		server.On(http.MethodGet, "/a").Times(2)
+		server.On(http.MethodGet, "/b").Times(0)
+
+		WithDefaultClient().MakeRequest(Get(server.URL + "/a")).ExpectIt(t)
+
+		r.cleanup()
+		assert(t, r.reporter["Errorf"] == 1, "must report unmet expectation")
+	})
+
+	t.Run("should fail reply and report builder errors", func(t *testing.T) {
+		var r = &cleaner{reporter: make(reporter)}
+		var server = mock.NewServer(r)
+		server.On(http.MethodGet, "/a").Respond(http.StatusOK, builders.WithJson(func() {}))
+		server.On(http.MethodGet, "/b").Template("{{ .Params.id")
+
+		WithDefaultClient().MakeRequest(Get(server.URL+"/a")).ExpectIt(t, ToHaveStatus(http.StatusInternalServerError))
+		WithDefaultClient().MakeRequest(Get(server.URL+"/b")).ExpectIt(t, ToHaveStatus(http.StatusInternalServerError))
+
+		r.cleanup()
+		assert(t, r.reporter["Errorf"] == 2, "must report invalid replies")
+	})
+
+	t.Run("should clear reply errors when reprogrammed", func(t *testing.T) {
+		var r = &cleaner{reporter: make(reporter)}
+		var server = mock.NewServer(r)
+		server.On(http.MethodGet, "/a").
+			Respond(http.StatusOK, builders.WithJson(func() {})).
+			Respond(http.StatusOK, builders.WithJson("ok"))
+
+		WithDefaultClient().MakeRequest(Get(server.URL+"/a")).ExpectIt(t, ToHaveStatus(http.StatusOK))
+
+		r.cleanup()
+		assert(t, r.reporter["Errorf"] == 0, "must not report replaced reply")
+	})
+
+	t.Run("should report calls out of order", func(t *testing.T) {
+		var r = &cleaner{reporter: make(reporter)}
+		var server = mock.NewServer(r).InOrder()
+		server.On(http.MethodGet, "/a").Once()
+		server.On(http.MethodGet, "/b").Once()
+
+		WithDefaultClient().MakeRequest(Get(server.URL + "/b")).ExpectIt(t)
+		WithDefaultClient().MakeRequest(Get(server.URL + "/a")).ExpectIt(t)
+
+		r.cleanup()
+		assert(t, r.reporter["Errorf"] == 1, "must report out of order call")
+		assert(t, len(server.Calls()) == 2, "must record all calls")
+	})
+}
+
+// executors.CleanupT implementation that records cleanup functions
+type cleaner struct {
+	reporter
+	fns []func()
+}
+
+func (c *cleaner) Cleanup(fn func()) { c.fns = append(c.fns, fn) }
+func (c *cleaner) cleanup() {
+	for _, fn := range c.fns {
+		fn()
+	}
+}
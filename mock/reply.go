@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+// reply defines the response written by the server when a stub matches.
+// Use Stub.Respond(...), Stub.Template(...) and Stub.Delay(...) to program it.
+type reply struct {
+	status   int
+	header   http.Header
+	body     []byte
+	template *template.Template
+	delay    time.Duration
+	err      error // set if the reply could not be programmed; the server then replies with a 500
+}
+
+// TemplateData is the value templates set using Stub.Template(...) are executed with.
+type TemplateData struct {
+	Request *http.Request
+	Params  map[string]string
+	Body    string
+	Json    interface{} // request body decoded as json, or nil if it isn't valid json
+}
+
+// build applies the given builders to a blank request, and programs the reply with the resulting header and body
+func (reply *reply) build(builders []httpx.RequestBuilder) {
+	var request = &http.Request{Header: make(http.Header), Body: http.NoBody}
+	for _, fn := range builders {
+		if err := fn(request); err != nil {
+			reply.err = fmt.Errorf("builder: %v", err)
+			return
+		}
+	}
+
+	var body, err = ioutil.ReadAll(request.Body)
+	if err != nil {
+		reply.err = fmt.Errorf("failed to read body: %v", err)
+		return
+	}
+	reply.header, reply.body = request.Header, body
+}
+
+// write writes the reply for the given call
+func (reply reply) write(writer http.ResponseWriter, call *Call) {
+	if reply.err != nil {
+		http.Error(writer, fmt.Sprintf("mock: %s: %v", call.Stub, reply.err), http.StatusInternalServerError)
+		return
+	}
+
+	if reply.delay > 0 {
+		sleep(call.Request, reply.delay)
+	}
+
+	var body = reply.body
+	if reply.template != nil {
+		// the template gets a copy of the request, so that reading its body doesn't affect the recorded call
+		var request = call.Request.Clone(call.Request.Context())
+		request.Body = ioutil.NopCloser(bytes.NewReader(call.Body))
+
+		var data = &TemplateData{Request: request, Params: call.Params, Body: string(call.Body)}
+		_ = json.Unmarshal(call.Body, &data.Json)
+
+		var buf bytes.Buffer
+		if err := reply.template.Execute(&buf, data); err != nil {
+			http.Error(writer, fmt.Sprintf("mock: failed to execute template: %v", err), http.StatusInternalServerError)
+			return
+		}
+		body = buf.Bytes()
+	}
+
+	for name, values := range reply.header {
+		for _, v := range values {
+			writer.Header().Add(name, v)
+		}
+	}
+	writer.WriteHeader(reply.status)
+	_, _ = writer.Write(body)
+}
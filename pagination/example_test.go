@@ -0,0 +1,32 @@
+package pagination_test
+
+import (
+	"fmt"
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/pagination"
+	"net/http"
+)
+
+func Example_cursor() {
+	// a list endpoint serving 5 users, 2 at a time, with a cursor pointing to the next page
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var pages = map[string]string{
+			"":  `{"data": [{"id": 1}, {"id": 2}], "meta": {"total": 5, "next_cursor": "b"}}`,
+			"b": `{"data": [{"id": 3}, {"id": 4}], "meta": {"total": 5, "next_cursor": "c"}}`,
+			"c": `{"data": [{"id": 5}], "meta": {"total": 5}}`,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("cursor")]))
+	})
+
+	var tr = pagination.Traverse(WithHandler(handler), Get("/users?limit=2"),
+		pagination.ItemsAt("data"), pagination.Using(pagination.Cursor("meta.next_cursor", "cursor")),
+	)
+
+	fmt.Println(len(tr.Pages), len(tr.Items))
+	fmt.Println(pagination.NoDuplicates("id")(tr), pagination.CountMatchesField("meta.total")(tr))
+	// Output:
+	// 3 5
+	// <nil> <nil>
+}
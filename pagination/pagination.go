@@ -0,0 +1,158 @@
+// Package pagination allows traversing paginated list endpoints and asserting invariants across all their pages.
+//
+// Traverse(...) follows pages, using one of the bundled strategies (Link headers, a cursor in the body,
+// or page / offset query parameters), until there are no more pages (or a cap is reached) and aggregates
+// the items from every page,
+//
+//  pagination.Traverse(WithHandler(handler), Get("/users?limit=10"),
+//    pagination.ItemsAt("data"), pagination.Using(pagination.Cursor("meta.next_cursor", "cursor")),
+//  ).ExpectIt(t, pagination.NoDuplicates("id"), pagination.CountMatchesField("meta.total"))
+//
+// Every page is fetched using the same ExecFn, and so any executor (including an in-memory handler) can be used.
+package pagination // import "go.riyazali.net/httpx/pagination"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"net/url"
+)
+
+// Page is a single page fetched during a traversal.
+type Page struct {
+	// Number is the (1-based) position of the page in the traversal
+	Number int
+
+	// Result is the result of fetching the page and running the per-page assertions on it
+	Result *httpx.Result
+
+	// Doc is the decoded json body of the page, and Items are the items found in it
+	Doc   interface{}
+	Items []interface{}
+}
+
+// Strategy defines a function that returns the url of the page following the given one, or nil if it's the last page.
+type Strategy func(page *Page) (*url.URL, error)
+
+// Config defines how pages are traversed by Traverse(...). Use the option functions in this package to customise it.
+type Config struct {
+	// Next is the strategy used to find the next page (defaults to FollowLinks())
+	Next Strategy
+
+	// Items is the json path (see helpers.LookupJsonPath(...)) to the array of items in the body
+	// (defaults to the body itself)
+	Items string
+
+	// MaxPages is the maximum number of pages to fetch (defaults to 100)
+	MaxPages int
+
+	// Builders are applied to the request for every page, and Assertions are run on the response of every page
+	Builders   []httpx.RequestBuilder
+	Assertions []httpx.Assertion
+}
+
+// ItemsAt sets the json path to the array of items in the body of every page.
+func ItemsAt(path string) func(*Config) {
+	return func(c *Config) {
+		c.Items = path
+	}
+}
+
+// MaxPages sets the maximum number of pages to fetch.
+func MaxPages(n int) func(*Config) {
+	return func(c *Config) {
+		c.MaxPages = n
+	}
+}
+
+// WithBuilders adds the given RequestBuilders, which are applied to the request for every page.
+func WithBuilders(builders ...httpx.RequestBuilder) func(*Config) {
+	return func(c *Config) {
+		c.Builders = append(c.Builders, builders...)
+	}
+}
+
+// ExpectEachPage adds the given assertions, which are run on the response of every page.
+func ExpectEachPage(assertions ...httpx.Assertion) func(*Config) {
+	return func(c *Config) {
+		c.Assertions = append(c.Assertions, assertions...)
+	}
+}
+
+// Using sets the strategy used to find the next page.
+func Using(strategy Strategy) func(*Config) {
+	return func(c *Config) {
+		c.Next = strategy
+	}
+}
+
+// Traverse fetches the page created by the given factory, and the ones following it, using the given ExecFn,
+// stopping when there are no more pages, when MaxPages pages have been fetched, or on the first error.
+// Subsequent pages are requested with the same method as the first page.
+func Traverse(fn httpx.ExecFn, factory httpx.RequestFactory, opts ...func(*Config)) *Traversal {
+	var config = Config{Next: FollowLinks(), MaxPages: 100}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	var traversal = &Traversal{}
+	var visited = make(map[string]bool)
+	for number := 1; ; number++ {
+		var page = &Page{Number: number}
+		page.Result = fn.MakeRequest(factory, config.Builders...).Evaluate(config.Assertions...)
+		traversal.Pages = append(traversal.Pages, page)
+		if page.Result.Err != nil {
+			traversal.Err = fmt.Errorf("pagination: page %d: %v", number, page.Result.Err)
+			return traversal
+		}
+
+		var request = page.Result.Request
+		visited[request.URL.String()] = true
+		if err := page.decode(config.Items); err != nil {
+			traversal.Err = fmt.Errorf("pagination: page %d: %v", number, err)
+			return traversal
+		}
+		traversal.Items = append(traversal.Items, page.Items...)
+
+		var next, err = config.Next(page)
+		switch {
+		case err != nil:
+			traversal.Err = fmt.Errorf("pagination: page %d: %v", number, err)
+			return traversal
+		case next == nil:
+			return traversal
+		case visited[next.String()]:
+			traversal.Err = fmt.Errorf("pagination: page %d: next page %s was already visited", number, next)
+			return traversal
+		case number >= config.MaxPages:
+			traversal.Truncated = true
+			return traversal
+		}
+		factory = httpx.Using(request.Method, next.String(), nil)
+	}
+}
+
+// decode decodes the page's body and looks up the items at the given path
+func (p *Page) decode(path string) error {
+	if len(bytes.TrimSpace(p.Result.Body)) == 0 {
+		return nil // say, a 204 with no body
+	}
+	if err := json.Unmarshal(p.Result.Body, &p.Doc); err != nil {
+		return fmt.Errorf("failed to decode body: %v", err)
+	}
+
+	var items, err = LookupJsonPath(p.Doc, path)
+	if err != nil {
+		return fmt.Errorf("items: %v", err)
+	}
+	if items == nil {
+		return nil
+	}
+	var ok bool
+	if p.Items, ok = items.([]interface{}); !ok {
+		return fmt.Errorf("items: value at '%s' is not an array", path)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package pagination_test
+
+import (
+	"encoding/json"
+	"fmt"
+	. "go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/assertions"
+	. "go.riyazali.net/httpx/executors"
+	"go.riyazali.net/httpx/matchers"
+	"go.riyazali.net/httpx/pagination"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestingT implementation that logs it's method calls
+type reporter map[string]int
+
+func (r reporter) Errorf(_ string, _ ...interface{}) { r["Errorf"] = r["Errorf"] + 1 }
+func (r reporter) FailNow()                          { r["FailNow"] = r["FailNow"] + 1 }
+func (r reporter) Helper()                           {}
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+type user struct {
+	ID int `json:"id"`
+}
+
+// api returns a handler that serves n users, in pages of size 10, using ?page=, ?offset= or ?cursor=.
+// Pages are linked using Link headers, and the body contains the total and the next cursor.
+func api(n int, overlap bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query = r.URL.Query()
+		var offset, _ = strconv.Atoi(query.Get("offset"))
+		if p, err := strconv.Atoi(query.Get("page")); err == nil {
+			offset = (p - 1) * 10
+		}
+		if c, err := strconv.Atoi(query.Get("cursor")); err == nil {
+			offset = c
+		}
+
+		var users = []user{}
+		for i := offset; i < offset+10 && i < n; i++ {
+			users = append(users, user{ID: i + 1})
+		}
+
+		var links []string
+		var page = offset/10 + 1
+		var last = (n + 9) / 10
+		if page < last {
+			links = append(links, fmt.Sprintf(`</users?page=%d>; rel="next"`, page+1))
+		}
+		if page > 1 {
+			links = append(links, fmt.Sprintf(`</users?page=%d>; rel="prev"`, page-1))
+		}
+		links = append(links, fmt.Sprintf(`</users?page=%d>; rel="last"`, last))
+		for _, link := range links {
+			w.Header().Add("Link", link)
+		}
+
+		var next interface{}
+		if offset+10 < n {
+			next = offset + 10
+			if overlap {
+				next = offset + 9
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", strconv.Itoa(n))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": users,
+			"meta": map[string]interface{}{"total": n, "next_cursor": next},
+		})
+	})
+}
+
+func TestTraverse(t *testing.T) {
+	var fn = WithHandler(api(25, false))
+	var checks = []pagination.Check{
+		pagination.Exhausted(),
+		pagination.Count(matchers.Equal(25)),
+		pagination.CountMatchesField("meta.total"),
+		pagination.CountMatchesHeader("X-Total-Count"),
+		pagination.NoDuplicates("id"),
+		pagination.OrderedBy("id", false),
+	}
+
+	t.Run("using links", func(t *testing.T) {
+		var tr = pagination.Traverse(fn, Get("http://example.com/users?page=1"),
+			pagination.ItemsAt("data"), pagination.ExpectEachPage(ToHaveStatus(http.StatusOK)))
+		tr.ExpectIt(t, append(checks, pagination.WellFormedLinks())...)
+		assert(t, len(tr.Pages) == 3, "must fetch all pages")
+	})
+
+	t.Run("using cursor", func(t *testing.T) {
+		pagination.Traverse(fn, Get("http://example.com/users"),
+			pagination.ItemsAt("data"), pagination.Using(pagination.Cursor("meta.next_cursor", "cursor")),
+		).ExpectIt(t, checks...)
+	})
+
+	t.Run("using page number", func(t *testing.T) {
+		var tr = pagination.Traverse(fn, Get("http://example.com/users"),
+			pagination.ItemsAt("data"), pagination.Using(pagination.PageNumber("page", 1)))
+		tr.ExpectIt(t, checks...)
+		assert(t, len(tr.Pages) == 4, "must stop at first empty page")
+	})
+
+	t.Run("using offset", func(t *testing.T) {
+		pagination.Traverse(fn, Get("http://example.com/users"),
+			pagination.ItemsAt("data"), pagination.Using(pagination.Offset("offset")),
+		).ExpectIt(t, checks...)
+	})
+}
+
+func TestTraverseFailures(t *testing.T) {
+	t.Run("should truncate at max pages", func(t *testing.T) {
+		var r = make(reporter)
+		var tr = pagination.Traverse(WithHandler(api(25, false)), Get("http://example.com/users?page=1"),
+			pagination.ItemsAt("data"), pagination.MaxPages(2))
+		tr.ExpectIt(r, pagination.Exhausted(), pagination.WellFormedLinks())
+		assert(t, tr.Truncated && len(tr.Pages) == 2, "must be truncated after 2 pages")
+		assert(t, r["Errorf"] == 1, "must only fail Exhausted")
+
+		var err = pagination.CountMatchesField("meta.total")(tr)
+		assert(t, err != nil && strings.HasPrefix(err.Error(), "count: "), "must fail count with prefixed error: %v", err)
+	})
+
+	t.Run("should detect duplicates across pages", func(t *testing.T) {
+		var r = make(reporter)
+		pagination.Traverse(WithHandler(api(25, true)), Get("http://example.com/users"),
+			pagination.ItemsAt("data"), pagination.Using(pagination.Cursor("meta.next_cursor", "cursor")),
+		).ExpectIt(r, pagination.NoDuplicates("id"), pagination.OrderedBy("id", false), pagination.OrderedBy("id", true))
+		assert(t, r["Errorf"] == 2, "must fail for duplicates and descending order, but allow equal values in ascending order")
+	})
+
+	t.Run("should detect loops", func(t *testing.T) {
+		var loop = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", `</users>; rel=next`)
+			_, _ = w.Write([]byte(`[]`))
+		})
+		var tr = pagination.Traverse(WithHandler(loop), Get("http://example.com/users"))
+		assert(t, tr.Err != nil && len(tr.Pages) == 1, "must stop on loop")
+	})
+
+	t.Run("should report page failures", func(t *testing.T) {
+		var r = make(reporter)
+		var tr = pagination.Traverse(WithHandler(api(5, false)), Get("http://example.com/users"),
+			pagination.ExpectEachPage(ToHaveStatus(http.StatusCreated)))
+		tr.ExpectIt(r)
+		assert(t, tr.Err != nil, "must fail if items are not an array")
+		assert(t, r["Errorf"] == 2, "must report error and failed assertion")
+	})
+
+	t.Run("should detect malformed links", func(t *testing.T) {
+		var broken = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", `</users?page=1>; rel="prev"`)
+			_, _ = w.Write([]byte(`[1, 2]`))
+		})
+		var r = make(reporter)
+		pagination.Traverse(WithHandler(broken), Get("http://example.com/users")).ExpectIt(r, pagination.WellFormedLinks())
+		assert(t, r["Errorf"] == 1, "must fail for prev link on first page")
+	})
+}
@@ -0,0 +1,99 @@
+package pagination
+
+import (
+	"fmt"
+	. "go.riyazali.net/httpx/helpers"
+	"net/url"
+	"strconv"
+)
+
+// FollowLinks returns a Strategy that follows the rel="next" link in the Link header (see RFC 8288),
+// resolved relative to the url of the current page.
+func FollowLinks() Strategy {
+	return func(page *Page) (*url.URL, error) {
+		var links, err = ParseLinks(page.Result.Response.Header.Values("Link")...)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			if link.HasRel("next") {
+				var next, err = page.Result.Request.URL.Parse(link.URI)
+				if err != nil {
+					return nil, fmt.Errorf("link: invalid next link '%s': %v", link.URI, err)
+				}
+				return next, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// Cursor returns a Strategy that reads the cursor at the given json path in the body, and requests the next page
+// by setting the given query parameter to it. The last page is the one without a cursor (ie. one where the path
+// is missing, or the cursor is null or an empty string).
+func Cursor(path, param string) Strategy {
+	return func(page *Page) (*url.URL, error) {
+		var cursor, err = LookupJsonPath(page.Doc, path)
+		if err != nil || cursor == nil || cursor == "" {
+			return nil, nil
+		}
+		switch cursor.(type) {
+		case string, float64:
+			return withParam(page, param, fmt.Sprint(cursor)), nil
+		}
+		return nil, fmt.Errorf("cursor: value at '%s' is neither a string nor a number", path)
+	}
+}
+
+// PageNumber returns a Strategy that requests the next page by incrementing the page number in the given query
+// parameter (which defaults to first, if not set on the request). The last page is the first one without any items.
+func PageNumber(param string, first int) Strategy {
+	return func(page *Page) (*url.URL, error) {
+		if len(page.Items) == 0 {
+			return nil, nil
+		}
+		var current, err = intParam(page, param, first)
+		if err != nil {
+			return nil, err
+		}
+		return withParam(page, param, strconv.Itoa(current+1)), nil
+	}
+}
+
+// Offset returns a Strategy that requests the next page by incrementing the offset in the given query parameter
+// (which defaults to 0, if not set on the request) by the number of items in the current page.
+// The last page is the first one without any items.
+func Offset(param string) Strategy {
+	return func(page *Page) (*url.URL, error) {
+		if len(page.Items) == 0 {
+			return nil, nil
+		}
+		var current, err = intParam(page, param, 0)
+		if err != nil {
+			return nil, err
+		}
+		return withParam(page, param, strconv.Itoa(current+len(page.Items))), nil
+	}
+}
+
+// intParam returns the value of the given integer query parameter in the page's request url
+func intParam(page *Page, param string, def int) (int, error) {
+	var value = page.Result.Request.URL.Query().Get(param)
+	if value == "" {
+		return def, nil
+	}
+	var n, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for parameter '%s'", value, param)
+	}
+	return n, nil
+}
+
+// withParam returns a copy of the page's request url with the given query parameter set to value
+func withParam(page *Page, param, value string) *url.URL {
+	var next = *page.Result.Request.URL
+	var query = next.Query()
+	query.Set(param, value)
+	next.RawQuery = query.Encode()
+	return &next
+}
@@ -0,0 +1,275 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.riyazali.net/httpx"
+	. "go.riyazali.net/httpx/helpers"
+	"go.riyazali.net/httpx/matchers"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Traversal contains the pages fetched by Traverse(...) and the items aggregated from them.
+type Traversal struct {
+	// Pages that were fetched, in order
+	Pages []*Page
+
+	// Items from all the pages, in order
+	Items []interface{}
+
+	// Err is set if the traversal was stopped because of an error (say, a page couldn't be fetched or decoded)
+	Err error
+
+	// Truncated is set if the traversal was stopped after fetching MaxPages pages, even though there were more pages
+	Truncated bool
+}
+
+// ExpectIt reports the traversal's error (if any) and the failures of per-page assertions to t,
+// and then runs the given checks, reporting any failures.
+func (tr *Traversal) ExpectIt(t httpx.TestingT, checks ...Check) {
+	t.Helper()
+	if tr.Err != nil {
+		t.Errorf("%v", tr.Err)
+	}
+	for _, page := range tr.Pages {
+		for _, err := range page.Result.Failures() {
+			if err != page.Result.Err { // already reported as part of tr.Err
+				t.Errorf("pagination: page %d: assertion: %v", page.Number, err)
+			}
+		}
+	}
+	for _, fn := range checks {
+		if err := fn(tr); err != nil {
+			t.Errorf("pagination: %v (%d items in %d pages)", err, len(tr.Items), len(tr.Pages))
+		}
+	}
+}
+
+// Check defines a function that performs some assertion on a traversal.
+type Check func(*Traversal) error
+
+// Exhausted returns a Check that asserts that all the pages were fetched, ie. the traversal wasn't truncated.
+func Exhausted() Check {
+	return func(tr *Traversal) error {
+		return AssertThat(!tr.Truncated, "traversal truncated after %d pages", len(tr.Pages))
+	}
+}
+
+// Count returns a Check that checks the total number of items using the given matcher.
+func Count(m matchers.Matcher) Check {
+	return func(tr *Traversal) error {
+		if err := m(len(tr.Items)); err != nil {
+			return fmt.Errorf("count: %v", err)
+		}
+		return nil
+	}
+}
+
+// CountMatchesField returns a Check that asserts that the total number of items is equal to the number
+// at the given json path in the body of the first page (like "meta.total").
+func CountMatchesField(path string) Check {
+	return func(tr *Traversal) error {
+		if len(tr.Pages) == 0 {
+			return fmt.Errorf("count: no pages fetched")
+		}
+		var total, err = LookupJsonPath(tr.Pages[0].Doc, path)
+		if err != nil {
+			return fmt.Errorf("count: %v", err)
+		}
+		if err = matchers.Equal(total)(len(tr.Items)); err != nil {
+			return fmt.Errorf("count: %v", err)
+		}
+		return nil
+	}
+}
+
+// CountMatchesHeader returns a Check that asserts that the total number of items is equal to the value
+// of the given header in the response of the first page (like X-Total-Count).
+func CountMatchesHeader(name string) Check {
+	return func(tr *Traversal) error {
+		if len(tr.Pages) == 0 || tr.Pages[0].Result.Response == nil {
+			return fmt.Errorf("count: no pages fetched")
+		}
+		var value = tr.Pages[0].Result.Response.Header.Get(name)
+		var total, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("count: invalid %s header '%s'", name, value)
+		}
+		return AssertThat(total == len(tr.Items), "count: %d items not equal to %s (%d)", len(tr.Items), name, total)
+	}
+}
+
+// NoDuplicates returns a Check that asserts that no item appears more than once across all pages. Items are identified
+// by the value at the given json path within each item (like "id"), or by the whole item if the path is empty.
+func NoDuplicates(key string) Check {
+	return func(tr *Traversal) error {
+		var seen = make(map[string]int)
+		for i, item := range tr.Items {
+			var id, err = itemKey(item, key)
+			if err != nil {
+				return fmt.Errorf("duplicates: item %d: %v", i, err)
+			}
+			if j, ok := seen[id]; ok {
+				return fmt.Errorf("duplicates: item %d (%s) is a duplicate of item %d", i, id, j)
+			}
+			seen[id] = i
+		}
+		return nil
+	}
+}
+
+// OrderedBy returns a Check that asserts that the items, across all pages, are sorted by the value at the given
+// json path within each item. Values must either all be numbers or all be strings. Equal values are allowed.
+func OrderedBy(key string, descending bool) Check {
+	return func(tr *Traversal) error {
+		var prev interface{}
+		for i, item := range tr.Items {
+			var value, err = LookupJsonPath(item, key)
+			if err != nil {
+				return fmt.Errorf("order: item %d: %v", i, err)
+			}
+			if i > 0 {
+				var cmp int
+				if cmp, err = compare(prev, value); err != nil {
+					return fmt.Errorf("order: item %d: %v", i, err)
+				}
+				if (cmp > 0 && !descending) || (cmp < 0 && descending) {
+					return fmt.Errorf("order: item %d (%v) out of order after %v", i, value, prev)
+				}
+			}
+			prev = value
+		}
+		return nil
+	}
+}
+
+// WellFormedLinks returns a Check that asserts that every page has a well-formed Link header where,
+//
+//  - rel="next" is present on every page but the last, and points to the following page
+//  - rel="prev" is present on every page but the first, and points to the preceding page
+//  - rel="first" and rel="last" (if present) point to the same url on every page,
+//    and rel="last" points to the last page (unless the traversal was truncated)
+func WellFormedLinks() Check {
+	return func(tr *Traversal) error {
+		var first, last *url.URL
+		for i, page := range tr.Pages {
+			if page.Result.Response == nil {
+				return fmt.Errorf("links: page %d: no response", page.Number)
+			}
+
+			var links, err = resolveLinks(page)
+			if err != nil {
+				return fmt.Errorf("links: page %d: %v", page.Number, err)
+			}
+
+			var isLast = i == len(tr.Pages)-1 && !tr.Truncated
+			if err = expectLink(links["next"], "next", !isLast, following(tr, i, 1)); err != nil {
+				return fmt.Errorf("links: page %d: %v", page.Number, err)
+			}
+			if err = expectLink(links["prev"], "prev", i > 0, following(tr, i, -1)); err != nil {
+				return fmt.Errorf("links: page %d: %v", page.Number, err)
+			}
+
+			for _, l := range []struct {
+				rel      string
+				expected **url.URL
+			}{{"first", &first}, {"last", &last}} {
+				if u := links[l.rel]; u != nil && *l.expected != nil && !sameURL(u, *l.expected) {
+					return fmt.Errorf("links: page %d: %s link (%s) differs from previous pages (%s)", page.Number, l.rel, u, *l.expected)
+				} else if u != nil {
+					*l.expected = u
+				}
+			}
+			if isLast && last != nil && !sameURL(last, page.Result.Request.URL) {
+				return fmt.Errorf("links: last link (%s) does not point to the last page (%s)", last, page.Result.Request.URL)
+			}
+		}
+		return nil
+	}
+}
+
+// resolveLinks parses the page's Link header and returns the resolved urls of its links, keyed by relation type
+func resolveLinks(page *Page) (map[string]*url.URL, error) {
+	var links, err = ParseLinks(page.Result.Response.Header.Values("Link")...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved = make(map[string]*url.URL)
+	for _, link := range links {
+		var u, err = page.Result.Request.URL.Parse(link.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid link '%s': %v", link.URI, err)
+		}
+		for _, rel := range link.Rels() {
+			if _, ok := resolved[rel]; ok {
+				return nil, fmt.Errorf("more than one %s link", rel)
+			}
+			resolved[rel] = u
+		}
+	}
+	return resolved, nil
+}
+
+// following returns the url of the page at the given offset from the i-th page, or nil if it wasn't fetched
+func following(tr *Traversal, i, offset int) *url.URL {
+	if j := i + offset; j >= 0 && j < len(tr.Pages) && tr.Pages[j].Result.Request != nil {
+		return tr.Pages[j].Result.Request.URL
+	}
+	return nil
+}
+
+// expectLink checks the presence of a link and, if target is known, that the link points to it
+func expectLink(link *url.URL, rel string, present bool, target *url.URL) error {
+	switch {
+	case present && link == nil:
+		return fmt.Errorf("%s link missing", rel)
+	case !present && link != nil:
+		return fmt.Errorf("unexpected %s link (%s)", rel, link)
+	case link != nil && target != nil && !sameURL(link, target):
+		return fmt.Errorf("%s link (%s) does not point to the %s page (%s)", rel, link, rel, target)
+	}
+	return nil
+}
+
+// sameURL reports whether the urls are equal, ignoring the order of query parameters
+func sameURL(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path && reflect.DeepEqual(a.Query(), b.Query())
+}
+
+// itemKey returns a string that identifies the value at the given path within the item
+func itemKey(item interface{}, path string) (string, error) {
+	var value, err = LookupJsonPath(item, path)
+	if err != nil {
+		return "", err
+	}
+	var b []byte
+	if b, err = json.Marshal(value); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// compare compares two json values which must either both be numbers or both be strings
+func compare(a, b interface{}) (int, error) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %v and %v", a, b)
+}
@@ -0,0 +1,56 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"go.riyazali.net/httpx/helpers"
+	"net/http"
+)
+
+// Require wraps the given assertions such that if any of them fails, the remaining assertions
+// (both the ones given to Require and the ones following it) are not evaluated, and the test is stopped
+// using t.FailNow(). Use it to guard assertions that don't make sense if a prior one fails, like,
+//
+//  MakeRequest(...).ExpectIt(t,
+//    Require(ToHaveStatus(http.StatusOK)), // no point decoding the body if status isn't ok
+//    BodyJson(func(x X) error { ... }),
+//  )
+func Require(assertions ...Assertion) Assertion {
+	return func(response *http.Response) error {
+		var rewind, err = helpers.BufferBody(response)
+		if err != nil {
+			return Fatal(fmt.Errorf("require: failed to read response body: %v", err))
+		}
+		defer rewind()
+
+		for _, fn := range assertions {
+			rewind()
+			if err = fn(response); err != nil {
+				return Fatal(err)
+			}
+		}
+		return nil
+	}
+}
+
+// Fatal wraps the given error such that, when returned from an Assertion, it aborts the remaining assertions.
+// It is what Require(...) uses internally, and is useful when writing custom assertions that wrap other assertions.
+func Fatal(err error) error {
+	if err == nil || IsFatal(err) {
+		return err
+	}
+	return &fatalError{err}
+}
+
+// IsFatal returns true if the given error (or any error it wraps) was created using Fatal(...)
+func IsFatal(err error) bool {
+	var f *fatalError
+	return errors.As(err, &f)
+}
+
+// fatalError marks an error returned by an assertion as fatal
+type fatalError struct {
+	error
+}
+
+func (f *fatalError) Unwrap() error { return f.error }
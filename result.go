@@ -0,0 +1,224 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Result contains the outcome of making a request and running assertions on its response.
+//
+// Use Assertable.Evaluate(...) to get a Result instead of reporting to a TestingT. This allows
+// you to use httpx assertions programmatically, outside of go test (say, in a cli, a health-check daemon
+// or a custom reporter) and decide for yourself what to do with the failures.
+type Result struct {
+	// Request that was sent, and Response that was received. Either may be nil if Err is set.
+	Request  *http.Request
+	Response *http.Response
+
+	// Body is the buffered response body
+	Body []byte
+
+	// Err is set if the request could not be built or executed, or if its body could not be read.
+	// No assertions are run in that case.
+	Err error
+
+	// Elapsed is the time taken by the ExecFn to return a response, and
+	// ReadElapsed is the time taken to read the response body.
+	Elapsed     time.Duration
+	ReadElapsed time.Duration
+
+	// Outcomes of each assertion, in the order they were given.
+	Outcomes []Outcome
+
+	// Aborted is set if a Require(...) assertion failed and the remaining assertions were skipped
+	Aborted bool
+}
+
+// Outcome is the outcome of running a single assertion.
+type Outcome struct {
+	// Err is the error returned by the assertion, or nil if it passed
+	Err error
+
+	// Elapsed is the time taken to run the assertion
+	Elapsed time.Duration
+
+	// Skipped is set if the assertion was not run because a prior Require(...) assertion failed
+	Skipped bool
+}
+
+// Passed returns true if the request was executed successfully and all assertions passed.
+func (r *Result) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns all the errors, including the error executing the request (if any)
+// and the errors returned by the failed assertions.
+func (r *Result) Failures() (errs []error) {
+	if r.Err != nil {
+		errs = append(errs, r.Err)
+	}
+	for _, o := range r.Outcomes {
+		if o.Err != nil {
+			errs = append(errs, o.Err)
+		}
+	}
+	return errs
+}
+
+// evaluate reads the response body and runs the given assertions on the response,
+// returning a copy of the result with outcomes of each assertion.
+func (r *Result) evaluate(assertions []Assertion) *Result {
+	if r.Err == nil && r.Body == nil {
+		var start = time.Now()
+		var buf bytes.Buffer
+		_, err := buf.ReadFrom(r.Response.Body)
+		_ = r.Response.Body.Close() // make sure to close the original response body always
+		r.ReadElapsed = time.Since(start)
+		if err != nil {
+			r.Err = fmt.Errorf("httpx: failed to read body into buffer: %v", err)
+		}
+		r.Body = buf.Bytes()
+	}
+
+	var result = *r
+	result.Outcomes = nil
+	if result.Err != nil {
+		return &result
+	}
+
+	for _, fn := range assertions {
+		if result.Aborted {
+			result.Outcomes = append(result.Outcomes, Outcome{Skipped: true})
+			continue
+		}
+
+		// give every assertion a fresh reader so that we can have
+		// multiple assertions that could read response's body
+		result.Response.Body = ioutil.NopCloser(bytes.NewReader(result.Body))
+
+		var start = time.Now()
+		var err = fn(result.Response)
+		result.Outcomes = append(result.Outcomes, Outcome{Err: err, Elapsed: time.Since(start)})
+		result.Aborted = IsFatal(err)
+	}
+	result.Response.Body = ioutil.NopCloser(bytes.NewReader(result.Body))
+	return &result
+}
+
+// report reports the result to the given TestingT
+func (r *Result) report(t TestingT) {
+	t.Helper()
+	if r.Err != nil {
+		t.Errorf("%v", r.Err)
+		t.FailNow() // doesn't return
+		return
+	}
+	for _, o := range r.Outcomes {
+		if o.Err != nil {
+			t.Errorf("httpx: assertion: %v", o.Err)
+		}
+	}
+	if r.Aborted {
+		t.FailNow()
+	}
+}
+
+// reportAll reports all the failures in the result to the given TestingT as a single, structured error
+func (r *Result) reportAll(t TestingT) {
+	t.Helper()
+	if r.Err != nil {
+		r.report(t) // doesn't return
+		return
+	}
+
+	var failed = 0
+	var buf strings.Builder
+	for i, o := range r.Outcomes {
+		if o.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(&buf, "\n  [%d] %s", i+1, strings.Replace(o.Err.Error(), "\n", "\n      ", -1))
+		} else if o.Skipped {
+			_, _ = fmt.Fprintf(&buf, "\n  [%d] skipped", i+1)
+		}
+	}
+
+	if failed > 0 {
+		var context string
+		if r.Request != nil && r.Response != nil {
+			context = fmt.Sprintf(" for %s %s (%d)", r.Request.Method, r.Request.URL, r.Response.StatusCode)
+		}
+		t.Errorf("httpx: %d of %d assertion(s) failed%s:%s", failed, len(r.Outcomes), context, buf.String())
+	}
+	if r.Aborted {
+		t.FailNow()
+	}
+}
+
+// ExpectAll is like ExpectIt, except that it reports all the failed assertions together as a single, structured
+// error (with the request and response status for context), rather than reporting each failure separately.
+func (a Assertable) ExpectAll(t TestingT, assertions ...Assertion) {
+	t.Helper()
+	a.Evaluate(assertions...).reportAll(t)
+}
+
+// Evaluate runs the given assertions and returns a Result instead of reporting failures to a TestingT.
+//
+//  var result = WithDefaultClient().MakeRequest(Get("https://example.com/health")).Evaluate(ToHaveStatus(http.StatusOK))
+//  if !result.Passed() {
+//    // alert someone ...
+//  }
+func (a Assertable) Evaluate(assertions ...Assertion) *Result {
+	var c = &collector{}
+	a(c, assertions...)
+	return c.collected()
+}
+
+// collector is a TestingT that allows Assertables returned by MakeRequest(...) to hand over their Result.
+// It is passed to the Assertable as its TestingT, and found there by deliver(...), even if it has been
+// wrapped by a custom Assertable (see TestingT for how wrappers can make themselves transparent).
+type collector struct {
+	result *Result
+	errs   []string
+}
+
+func (c *collector) Errorf(format string, args ...interface{}) {
+	c.errs = append(c.errs, fmt.Sprintf(format, args...))
+}
+
+func (c *collector) FailNow() {}
+func (c *collector) Helper()  {}
+
+// collected returns the result handed over to the collector or, if the Assertable didn't hand over
+// one (say, it's a custom Assertable), a result built from whatever was reported to it
+func (c *collector) collected() *Result {
+	if c.result != nil {
+		return c.result
+	}
+	var result = &Result{}
+	for _, msg := range c.errs {
+		result.Outcomes = append(result.Outcomes, Outcome{Err: errors.New(msg)})
+	}
+	return result
+}
+
+// deliver hands over the result to the collector t is (or wraps), or else reports it to t
+func deliver(t TestingT, result *Result) {
+	t.Helper()
+	for next := t; next != nil; {
+		if c, ok := next.(*collector); ok {
+			c.result = result
+			return
+		}
+		var wrapper, ok = next.(interface{ Unwrap() TestingT })
+		if !ok {
+			break
+		}
+		next = wrapper.Unwrap()
+	}
+	result.report(t)
+}
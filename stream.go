@@ -0,0 +1,133 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// StreamAssertion defines an assertion that checks the response body incrementally, as it is being read,
+// rather than after it has been buffered into memory. Use these with StreamRequest(...) to test
+// large downloads or infinite streams.
+//
+// For every response, the StreamAssertion is invoked once to get an io.WriteCloser. Every chunk of the body
+// is then written to it as it is read, and Close() is called once the body is exhausted. An error returned
+// from Write(...) fails the assertion right away (and the writer receives no further chunks), whereas
+// an error returned from Close() fails it at the end. Close() is always called, even after a failed Write(...),
+// so that any resources held by the writer are released.
+//
+// A writer that doesn't need to see any more of the body can return ErrSatisfied from Write(...).
+// Once every writer has either failed or been satisfied, the rest of the body is never read.
+type StreamAssertion func(*http.Response) io.WriteCloser
+
+// ErrSatisfied can be returned from the Write(...) method of a StreamAssertion's writer to signal that it has
+// passed and needs no more of the body. It is not reported as a failure.
+var ErrSatisfied = errors.New("httpx: stream assertion satisfied")
+
+// Streamable is like Assertable, except that it runs StreamAssertions on the response body
+// without ever buffering it whole into memory.
+type Streamable func(TestingT, ...StreamAssertion)
+
+// ExpectIt allows us to implement fluent chaining with StreamRequest.
+//
+//  WithDefaultClient().StreamRequest(Get("https://example.com/large.iso")).ExpectIt(t, TotalSize(...), SHA256(...))
+func (s Streamable) ExpectIt(t TestingT, assertions ...StreamAssertion) {
+	t.Helper()
+	s(t, assertions...)
+}
+
+// Evaluate runs the given assertions and returns a Result instead of reporting failures to a TestingT.
+// The Result's Body is always nil, as the body is never buffered.
+func (s Streamable) Evaluate(assertions ...StreamAssertion) *Result {
+	var c = &collector{}
+	s(c, assertions...)
+	return c.collected()
+}
+
+// StreamRequest is like MakeRequest, except that the response body is streamed through the assertions
+// (see StreamAssertion) instead of being buffered into memory first.
+//
+//  WithDefaultClient().StreamRequest(Get("https://example.com/large.iso")).
+//    ExpectIt(t, assertions.TotalSize(matchers.Equal(4<<30)), assertions.DigestMatchesHeader())
+//
+// As the body can only be read once, the returned Streamable must only be used once.
+func (fn ExecFn) StreamRequest(factory RequestFactory, builders ...RequestBuilder) Streamable {
+	var result = fn.execute(factory, builders)
+	return func(t TestingT, assertions ...StreamAssertion) {
+		t.Helper()
+		deliver(t, result.stream(assertions))
+	}
+}
+
+// stream reads the response body in chunks, feeding each chunk to the given assertions,
+// and returns a copy of the result with outcomes of each assertion.
+func (r *Result) stream(assertions []StreamAssertion) *Result {
+	var result = *r
+	result.Outcomes = nil
+	if result.Err != nil {
+		return &result
+	}
+	if result.Response.Body == consumed {
+		result.Err = errConsumed
+		return &result
+	}
+
+	var writers = make([]io.WriteCloser, len(assertions))
+	var done = make([]bool, len(assertions))
+	result.Outcomes = make([]Outcome, len(assertions))
+	for i, fn := range assertions {
+		writers[i] = fn(result.Response)
+	}
+
+	var start = time.Now()
+	var active = len(writers)
+	var buf = make([]byte, 32*1024)
+	for active > 0 {
+		var n, err = result.Response.Body.Read(buf)
+		for i, w := range writers {
+			if n == 0 || done[i] {
+				continue
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				done[i], active = true, active-1
+				if !errors.Is(werr, ErrSatisfied) {
+					result.Outcomes[i].Err = werr
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			result.Err = fmt.Errorf("httpx: failed to read body: %v", err)
+			break
+		}
+	}
+	_ = result.Response.Body.Close()
+	result.ReadElapsed = time.Since(start)
+
+	// the body is gone; make sure nothing (including another call to stream) tries to read it again
+	result.Response.Body = consumed
+
+	for i, w := range writers {
+		if err := w.Close(); !done[i] && result.Err == nil {
+			result.Outcomes[i].Err = err
+		}
+	}
+	if result.Err != nil {
+		result.Outcomes = nil
+	}
+	return &result
+}
+
+var errConsumed = errors.New("httpx: response body has already been streamed")
+
+// consumed replaces the response body once it has been streamed
+var consumed io.ReadCloser = consumedBody{}
+
+type consumedBody struct{}
+
+func (consumedBody) Read([]byte) (int, error) { return 0, errConsumed }
+func (consumedBody) Close() error             { return nil }
@@ -0,0 +1,50 @@
+// Package table runs table-driven httpx tests, executing every case as a subtest of the given *testing.T.
+//
+// It lives in its own package so that the core httpx package doesn't depend on the testing package
+// (it only ever needs a TestingT).
+package table // import "go.riyazali.net/httpx/table"
+
+import (
+	"go.riyazali.net/httpx"
+	"testing"
+)
+
+// Case defines a single test case in a table-driven test. See Run(...) for details.
+type Case struct {
+	// Name of the case, used as the name of the subtest
+	Name string
+
+	// Request is the RequestFactory used to create the request, and Builders are applied to it before execution
+	Request  httpx.RequestFactory
+	Builders []httpx.RequestBuilder
+
+	// Assertions to perform on the response
+	Assertions []httpx.Assertion
+
+	// Parallel marks the case to be run in parallel with other parallel cases (using t.Parallel())
+	Parallel bool
+}
+
+// Run executes each of the given cases as a subtest of t, using the ExecFn to execute requests.
+// This saves you from re-implementing the same loop in every table-driven test, and makes
+// sure that every failure is reported in the context of the case that caused it.
+//
+//  table.Run(t, WithHandler(handler),
+//    table.Case{Name: "list users", Request: Get("/users"), Assertions: []Assertion{ToHaveStatus(http.StatusOK)}},
+//    table.Case{Name: "unknown user", Request: Get("/users/0"), Assertions: []Assertion{ToHaveStatus(http.StatusNotFound)}},
+//  )
+//
+// Make sure that the ExecFn is safe for concurrent use if any of the cases are marked as Parallel.
+func Run(t *testing.T, fn httpx.ExecFn, cases ...Case) {
+	t.Helper()
+	for _, c := range cases {
+		var c = c // capture range variable
+		t.Run(c.Name, func(t *testing.T) {
+			t.Helper()
+			if c.Parallel {
+				t.Parallel()
+			}
+			fn.MakeRequest(c.Request, c.Builders...).ExpectIt(t, c.Assertions...)
+		})
+	}
+}
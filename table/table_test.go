@@ -0,0 +1,44 @@
+package table_test
+
+import (
+	. "go.riyazali.net/httpx"
+	"go.riyazali.net/httpx/table"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func assert(t *testing.T, cond bool, msg string, args ...interface{}) {
+	t.Helper()
+	if !cond {
+		t.Errorf(msg, args...)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	var seen = make(map[string]bool)
+	var execer = ExecFn(func(request *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen[request.URL.Path] = true
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	var ok = Assertion(func(response *http.Response) error {
+		return nil
+	})
+
+	t.Run("cases", func(t *testing.T) {
+		table.Run(t, execer,
+			table.Case{Name: "first", Request: Get("/a"), Assertions: []Assertion{ok}},
+			table.Case{Name: "second", Request: Get("/b"), Parallel: true},
+			table.Case{Name: "third", Request: Get("/c"), Parallel: true, Builders: []RequestBuilder{
+				func(request *http.Request) error { request.URL.Path = "/d"; return nil },
+			}},
+		)
+	})
+
+	assert(t, len(seen) == 3, "must run all cases")
+	assert(t, seen["/d"], "must apply builders")
+}